@@ -0,0 +1,120 @@
+package ssmconfig
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// Cache is an in-memory cache of parameters keyed by resolved parameter name. A Cache can
+// be shared by multiple Providers and is safe for concurrent use.
+//
+// Parameters that Parameter Store returns as invalid are also cached so that defaults are
+// applied without requesting them again.
+type Cache struct {
+	// TTL is how long a parameter is cached.
+	TTL time.Duration
+
+	// NegativeTTL is how long an invalid parameter is cached. If it is zero TTL is used. If
+	// it is negative invalid parameters are not cached.
+	NegativeTTL time.Duration
+
+	// Now returns the current time. If it is nil time.Now is used.
+	Now func() time.Time
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+}
+
+// cacheKey identifies a cached parameter. Parameters fetched with and without decryption
+// are cached separately, as the values of SecureString parameters differ.
+type cacheKey struct {
+	name    string
+	decrypt bool
+}
+
+type cacheEntry struct {
+	param   *ssm.Parameter // nil if the parameter is invalid
+	expires time.Time
+}
+
+// NewCache returns a cache that stores parameters for ttl.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{TTL: ttl}
+}
+
+// Invalidate removes the named parameters from the cache.
+func (c *Cache) Invalidate(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		delete(c.entries, cacheKey{name, true})
+		delete(c.entries, cacheKey{name, false})
+	}
+}
+
+// Purge removes all parameters from the cache.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+func (c *Cache) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// lookup adds the unexpired cached parameters from names, fetched with the given decryption
+// setting, to params and invalidParams. The names that are not cached are returned.
+func (c *Cache) lookup(names []string, decrypt bool, params map[string]*ssm.Parameter, invalidParams map[string]struct{}) (missing []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for _, name := range names {
+		entry, ok := c.entries[cacheKey{name, decrypt}]
+		if !ok || !now.Before(entry.expires) {
+			missing = append(missing, name)
+			continue
+		}
+
+		if entry.param == nil {
+			invalidParams[name] = struct{}{}
+			continue
+		}
+		params[name] = entry.param
+	}
+	return missing
+}
+
+// store adds the parameters returned by Parameter Store, fetched with the given decryption
+// setting, to the cache.
+func (c *Cache) store(params []*ssm.Parameter, invalidParams []*string, decrypt bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[cacheKey]cacheEntry{}
+	}
+
+	now := c.now()
+	for _, param := range params {
+		c.entries[cacheKey{aws.StringValue(param.Name), decrypt}] = cacheEntry{param: param, expires: now.Add(c.TTL)}
+	}
+
+	negativeTTL := c.NegativeTTL
+	if negativeTTL == 0 {
+		negativeTTL = c.TTL
+	}
+	if negativeTTL < 0 {
+		return
+	}
+	for _, name := range invalidParams {
+		c.entries[cacheKey{aws.StringValue(name), decrypt}] = cacheEntry{expires: now.Add(negativeTTL)}
+	}
+}
@@ -0,0 +1,64 @@
+package ssmconfig_test
+
+import (
+	"testing"
+	"time"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_Process_cache(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := &ssmconfig.Cache{
+		TTL:         time.Minute,
+		NegativeTTL: 10 * time.Second,
+		Now:         func() time.Time { return now },
+	}
+	p := &ssmconfig.Provider{SSM: s.Client(), Cache: cache}
+
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+		S2 string `ssm:"/strings/s2" default:"default"`
+	}
+
+	process := func(want config, wantCalls int) {
+		t.Helper()
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c != want {
+			t.Errorf("Process() want %+v, have %+v", want, c)
+		}
+		if have := s.Calls("GetParameters"); have != wantCalls {
+			t.Errorf("Process() want %d GetParameters calls, have %d", wantCalls, have)
+		}
+	}
+
+	process(config{S1: "string1", S2: "default"}, 1)
+
+	// Both the valid and invalid parameters are cached.
+	s.Put("/base/strings/s1", "changed")
+	s.Put("/base/strings/s2", "string2")
+	process(config{S1: "string1", S2: "default"}, 1)
+
+	// Only the invalid parameter has expired.
+	now = now.Add(10 * time.Second)
+	process(config{S1: "string1", S2: "string2"}, 2)
+
+	now = now.Add(time.Minute)
+	process(config{S1: "changed", S2: "string2"}, 3)
+
+	s.Put("/base/strings/s1", "invalidated")
+	cache.Invalidate("/base/strings/s1")
+	process(config{S1: "invalidated", S2: "string2"}, 4)
+
+	s.Put("/base/strings/s1", "purged")
+	cache.Purge()
+	process(config{S1: "purged", S2: "string2"}, 5)
+}
@@ -0,0 +1,187 @@
+package ssmconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ProblemKind is the kind of a Problem found by Check.
+type ProblemKind int
+
+const (
+	// ProblemMissing is a required parameter that does not exist.
+	ProblemMissing ProblemKind = iota
+
+	// ProblemDecode is a parameter whose value cannot be decoded into its field.
+	ProblemDecode
+
+	// ProblemInsecure is a parameter that is not stored as required by the `secure` tag or
+	// the Provider's SecurePolicy.
+	ProblemInsecure
+
+	// ProblemDefault is a default value that cannot be decoded into its field.
+	ProblemDefault
+
+	// ProblemUnusedDefault is a default value that is not used because the parameter
+	// exists. It is a warning rather than an error.
+	ProblemUnusedDefault
+)
+
+// String returns the name of k, e.g. "missing".
+func (k ProblemKind) String() string {
+	switch k {
+	case ProblemMissing:
+		return "missing"
+	case ProblemDecode:
+		return "decode"
+	case ProblemInsecure:
+		return "insecure"
+	case ProblemDefault:
+		return "default"
+	case ProblemUnusedDefault:
+		return "unused-default"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes k as its name.
+func (k ProblemKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Warning reports whether problems of kind k are warnings rather than errors.
+func (k ProblemKind) Warning() bool {
+	return k == ProblemUnusedDefault
+}
+
+// Problem is a problem with the configuration of a field found by Check. It never
+// contains the values of SecureString parameters.
+type Problem struct {
+	Kind ProblemKind `json:"kind"`
+
+	// Field is the name of the struct field. It is empty for problems that are not
+	// specific to a field.
+	Field string `json:"field,omitempty"`
+
+	// Parameter is the resolved name of the field's parameter.
+	Parameter string `json:"parameter,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	level := "error"
+	if p.Kind.Warning() {
+		level = "warning"
+	}
+	if p.Field == "" {
+		return fmt.Sprintf("%s: %s", level, p.Message)
+	}
+	return fmt.Sprintf("%s: %s (%s): %s", level, p.Field, p.Parameter, p.Message)
+}
+
+// CheckResult is the result of Check.
+type CheckResult struct {
+	// Report describes where the value of each field came from.
+	Report *Report `json:"report"`
+
+	// Problems are the problems found, in field order.
+	Problems []Problem `json:"problems"`
+}
+
+// Failed reports whether any of the problems found are errors.
+func (r *CheckResult) Failed() bool {
+	for _, problem := range r.Problems {
+		if !problem.Kind.Warning() {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the problems found, one per line.
+func (r *CheckResult) String() string {
+	var b strings.Builder
+	for _, problem := range r.Problems {
+		b.WriteString(problem.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Check verifies that c can be processed with configPath. It is the same as Process,
+// except that it does not stop at the first problem: every field is resolved, and all
+// missing required parameters, undecodable values, parameters that are not secure as
+// required, undecodable defaults, and unused defaults are reported. c must be a pointer
+// to a struct, and is populated as by Process.
+//
+// An error is returned only if the config cannot be checked, e.g. because parameters
+// cannot be fetched. Last-known-good values are not used.
+func (p *Provider) Check(ctx context.Context, configPath string, c interface{}) (*CheckResult, error) {
+	spec, t, err := specFor(configPath, c)
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted, plain := p.requestNames(spec, nil)
+	res, err := p.getParameters(ctx, decrypted, plain)
+	if err != nil {
+		return nil, errors.Wrap(err, "ssmconfig: could not get parameters")
+	}
+
+	result := &CheckResult{Report: newReport(configPath, res, nil), Problems: []Problem{}}
+	if err := p.checkKeyIDs(ctx, spec, res.params, nil); err != nil {
+		result.Problems = append(result.Problems, Problem{Kind: ProblemInsecure, Message: err.Error()})
+	}
+
+	// Defaults are decoded into a separate value so that they do not overwrite c.
+	_, scratch, err := specFor(configPath, reflect.New(reflect.TypeOf(c).Elem()).Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range spec {
+		field := spec[i]
+		info, err := spec.setField(ctx, p, t, i, res.params, res.invalid)
+		result.Report.addField(info, res.params[info.Parameter])
+
+		problem := Problem{Field: field.field, Parameter: field.name}
+		param, exists := res.params[field.name]
+		if err != nil {
+			switch {
+			case !exists && field.required:
+				problem.Kind = ProblemMissing
+			case !exists:
+				// The default value was used and could not be decoded.
+				problem.Kind = ProblemDefault
+			case p.checkSecure(field, param) != nil:
+				problem.Kind = ProblemInsecure
+			default:
+				problem.Kind = ProblemDecode
+			}
+			problem.Message = err.Error()
+			result.Problems = append(result.Problems, problem)
+		}
+
+		if field.defaultValue == "" || !exists {
+			continue
+		}
+		if err := scratch.set(field.fieldPlan, field.defaultValue); err != nil {
+			problem.Kind = ProblemDefault
+			problem.Message = fmt.Sprintf("ssmconfig: the default value of field %s cannot be decoded", field.field)
+			if !field.sensitive {
+				problem.Message += ": " + err.Error()
+			}
+		} else {
+			problem.Kind = ProblemUnusedDefault
+			problem.Message = fmt.Sprintf("ssmconfig: the default value of field %s is not used because %s exists", field.field, field.name)
+		}
+		result.Problems = append(result.Problems, problem)
+	}
+	return result, nil
+}
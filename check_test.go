@@ -0,0 +1,99 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_Check(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/ok", "ok")
+	s.Put("/base/int", "not-an-int")
+	s.PutSecure("/base/secret-int", "hunter2")
+	s.Put("/base/plain", "plain")
+	s.Put("/base/overridden", "2")
+
+	type config struct {
+		OK         string `ssm:"/ok" required:"true"`
+		Missing    string `ssm:"/missing" required:"true"`
+		Int        int    `ssm:"/int"`
+		SecretInt  int    `ssm:"/secret-int"`
+		Plain      string `ssm:"/plain" secure:"true"`
+		BadDefault int    `ssm:"/bad-default" default:"ten"`
+		Overridden int    `ssm:"/overridden" default:"1"`
+		Defaulted  int    `ssm:"/defaulted" default:"3"`
+	}
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	var c config
+	result, err := p.Check(context.Background(), "/base", &c)
+	if err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+
+	var kinds []string
+	for _, problem := range result.Problems {
+		kinds = append(kinds, problem.Field+":"+problem.Kind.String())
+	}
+	want := []string{
+		"Missing:missing",
+		"Int:decode",
+		"SecretInt:decode",
+		"Plain:insecure",
+		"BadDefault:default",
+		"Overridden:unused-default",
+	}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Check() unexpected problems:\nwant %v\nhave %v", want, kinds)
+	}
+	if !result.Failed() {
+		t.Errorf("Failed() want true")
+	}
+
+	text := result.String()
+	if strings.Contains(text, "hunter2") {
+		t.Errorf("String() exposed a SecureString value:\n%s", text)
+	}
+	if !strings.Contains(text, "warning: Overridden (/base/overridden):") {
+		t.Errorf("String() missing warning in:\n%s", text)
+	}
+
+	// Every field was resolved, despite the problems.
+	if c.OK != "ok" || c.Overridden != 2 || c.Defaulted != 3 {
+		t.Errorf("Check() did not populate config: %+v", c)
+	}
+	if len(result.Report.Fields) != 8 {
+		t.Errorf("Check() want 8 fields in report, have %d", len(result.Report.Fields))
+	}
+}
+
+func TestProvider_Check_ok(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/value", "1")
+
+	type config struct {
+		Value   int `ssm:"/value" default:"2"`
+		Default int `ssm:"/default" default:"3"`
+	}
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	result, err := p.Check(context.Background(), "/base", &config{})
+	if err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+	if result.Failed() || len(result.Problems) != 1 {
+		t.Errorf("Check() want a single warning, have %+v", result.Problems)
+	}
+
+	s.Fail("GetParameters", "AccessDeniedException", 1)
+	if _, err := p.Check(context.Background(), "/base", &config{}); err == nil {
+		t.Errorf("Check() expected error when parameters cannot be fetched")
+	}
+}
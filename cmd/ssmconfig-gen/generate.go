@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+
+	"github.com/ianlopshire/go-ssm-config/internal/schema"
+	"github.com/pkg/errors"
+)
+
+const header = "// Code generated by ssmconfig-gen; DO NOT EDIT.\n\n"
+
+// generate returns the source of a file implementing ssmconfig.Generated for structs.
+func generate(structs []*schema.Struct) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(header)
+	fmt.Fprintf(&b, "package %s\n\n", structs[0].Package)
+	b.WriteString("import ssmconfig \"github.com/ianlopshire/go-ssm-config\"\n")
+
+	for _, s := range structs {
+		if err := generateStruct(&b, s); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := format.Source(b.Bytes())
+	return src, errors.Wrap(err, "could not format generated code")
+}
+
+func generateStruct(b *bytes.Buffer, s *schema.Struct) error {
+	fields := "_ssmconfigFields" + s.Name
+
+	fmt.Fprintf(b, "\nvar %s = []ssmconfig.GeneratedField{\n", fields)
+	for _, f := range s.Fields {
+		if !f.Exported() {
+			return errors.Errorf("%s.%s: fields with an ssm tag must be exported", s.Name, f.Name)
+		}
+		fmt.Fprintf(b, "{Name: %q, Tag: %s},\n", f.Name, quote(f.Tag))
+	}
+	b.WriteString("}\n")
+
+	fmt.Fprintf(b, "\n// SSMFields implements ssmconfig.Generated.\n")
+	fmt.Fprintf(b, "func (c *%s) SSMFields() []ssmconfig.GeneratedField {\n", s.Name)
+	fmt.Fprintf(b, "return %s\n}\n", fields)
+
+	fmt.Fprintf(b, "\n// SSMSet implements ssmconfig.Generated.\n")
+	fmt.Fprintf(b, "func (c *%s) SSMSet(i int, s string) error {\n", s.Name)
+	b.WriteString("switch i {\n")
+	for i, f := range s.Fields {
+		fmt.Fprintf(b, "case %d:\n", i)
+		generateDecode(b, f)
+	}
+	b.WriteString("}\nreturn nil\n}\n")
+	return nil
+}
+
+// generateDecode writes the statements that decode s into the field f.
+func generateDecode(b *bytes.Buffer, f schema.Field) {
+	// Types declared in other packages, or whose types could not be resolved, are decoded
+	// using reflection.
+	if !f.Local {
+		fmt.Fprintf(b, "return ssmconfig.DecodeField(&c.%s, s)\n", f.Name)
+		return
+	}
+
+	var decode, result string
+	switch f.Kind {
+	case "string":
+		decode, result = "", "string"
+	case "int", "int8", "int16", "int32", "int64":
+		decode, result = fmt.Sprintf("ssmconfig.DecodeInt(s, %q)", f.Type), "int64"
+	case "float32":
+		decode, result = fmt.Sprintf("ssmconfig.DecodeFloat(s, 32, %q)", f.Type), "float64"
+	case "float64":
+		decode, result = fmt.Sprintf("ssmconfig.DecodeFloat(s, 64, %q)", f.Type), "float64"
+	case "bool":
+		decode, result = fmt.Sprintf("ssmconfig.DecodeBool(s, %q)", f.Type), "bool"
+	default:
+		fmt.Fprintf(b, "return ssmconfig.DecodeUnsupported(s, %q)\n", f.Type)
+		return
+	}
+
+	v := "v"
+	if decode == "" {
+		v = "s"
+	} else {
+		fmt.Fprintf(b, "v, err := %s\n", decode)
+		b.WriteString("if err != nil {\nreturn err\n}\n")
+	}
+	if f.Type != result {
+		v = localName(f.Type) + "(" + v + ")"
+	}
+	fmt.Fprintf(b, "c.%s = %s\n", f.Name, v)
+}
+
+// generateTest returns the source of a test file that verifies the generated code for
+// structs.
+func generateTest(structs []*schema.Struct) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(header)
+	fmt.Fprintf(&b, "package %s\n\n", structs[0].Package)
+	b.WriteString("import (\n\"testing\"\n\nssmconfig \"github.com/ianlopshire/go-ssm-config\"\n)\n\n")
+
+	b.WriteString("func TestSSMConfigGenerated(t *testing.T) {\n")
+	b.WriteString("for _, g := range []ssmconfig.Generated{\n")
+	for _, s := range structs {
+		fmt.Fprintf(&b, "&%s{},\n", s.Name)
+	}
+	b.WriteString("} {\nif err := ssmconfig.VerifyGenerated(g); err != nil {\nt.Error(err)\n}\n}\n}\n")
+
+	src, err := format.Source(b.Bytes())
+	return src, errors.Wrap(err, "could not format generated test")
+}
+
+// localName returns the name of a type declared in the current package, e.g. "Level" for
+// "config.Level".
+func localName(typ string) string {
+	return typ[strings.LastIndex(typ, ".")+1:]
+}
+
+// quote returns s as a Go string literal, preferring a raw string.
+func quote(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,80 @@
+// Command ssmconfig-gen generates reflection-free code for populating config structs with
+// ssmconfig.
+//
+// Usage:
+//
+//	ssmconfig-gen -type Config [-output file] [-test] [dir]
+//
+// The generated code implements ssmconfig.Generated, which Provider.Process uses in place
+// of reflection. It is intended to be run by go generate:
+//
+//	//go:generate go run github.com/ianlopshire/go-ssm-config/cmd/ssmconfig-gen -type Config -test
+//
+// With -test a test file is also generated that verifies the generated code agrees with
+// the reflective path. The generated code must be regenerated whenever the struct changes.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ianlopshire/go-ssm-config/internal/schema"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("ssmconfig-gen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; must be set")
+	output := flag.String("output", "", "output file name; default <dir>/<type>_ssmconfig.go")
+	test := flag.Bool("test", false, "also generate a test that verifies the generated code")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ssmconfig-gen -type T [-output file] [-test] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	names := strings.Split(*typeNames, ",")
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(names[0])+"_ssmconfig.go")
+	}
+
+	structs, err := schema.Load(dir, names...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(structs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	if *test {
+		src, err := generateTest(structs)
+		if err != nil {
+			log.Fatal(err)
+		}
+		testOutput := strings.TrimSuffix(*output, ".go") + "_test.go"
+		if err := ioutil.WriteFile(testOutput, src, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
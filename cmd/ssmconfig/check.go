@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/pkg/errors"
+)
+
+func runCheck(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+	var (
+		sf         structFlags
+		configPath string
+		asJSON     bool
+		p          ssmconfig.Provider
+	)
+	sf.register(fs)
+	fs.StringVar(&configPath, "path", "", "base path the config is processed with")
+	fs.BoolVar(&asJSON, "json", false, "print the result as JSON")
+	fs.BoolVar(&p.DisableDecryption, "disable-decryption", false, "request fields without decryption unless tagged decrypt:\"true\"")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if configPath == "" {
+		return usageError("-path must be set")
+	}
+
+	s, err := sf.load(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	c, err := newConfig(s)
+	if err != nil {
+		return err
+	}
+
+	if p.SSM, err = newSSM(); err != nil {
+		return err
+	}
+	result, err := p.Check(context.Background(), configPath, c)
+	if err != nil {
+		return err
+	}
+
+	// Values cannot be decoded into fields of unsupported types, but the errors name the
+	// placeholder type rather than the type of the field.
+	unsupported := unsupportedFields(s)
+	for i, problem := range result.Problems {
+		typ, ok := unsupported[problem.Field]
+		if !ok || (problem.Kind != ssmconfig.ProblemDecode && problem.Kind != ssmconfig.ProblemDefault) {
+			continue
+		}
+		result.Problems[i].Message = fmt.Sprintf("ssmconfig: field %s has type %s, which cannot be decoded", problem.Field, typ)
+	}
+
+	if asJSON {
+		err = writeJSON(stdout, result)
+	} else {
+		_, err = fmt.Fprintf(stdout, "%s%s: %d fields checked\n", result, s.Name, len(result.Report.Fields))
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Failed() {
+		return errors.New("configuration has problems")
+	}
+	return nil
+}
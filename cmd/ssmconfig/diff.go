@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"io/ioutil"
+	"strconv"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/pkg/errors"
+)
+
+func runDiff(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+	var (
+		sf         structFlags
+		configPath string
+		valuesFile string
+		asJSON     bool
+	)
+	sf.register(fs)
+	fs.StringVar(&configPath, "path", "", "base path the config is processed with")
+	fs.StringVar(&valuesFile, "values", "", "JSON file of desired parameter values, keyed by field name")
+	fs.BoolVar(&asJSON, "json", false, "print the diff as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if configPath == "" {
+		return usageError("-path must be set")
+	}
+	if valuesFile == "" {
+		return usageError("-values must be set")
+	}
+
+	values, err := readValues(valuesFile)
+	if err != nil {
+		return err
+	}
+
+	s, err := sf.load(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	c, err := newConfig(s)
+	if err != nil {
+		return err
+	}
+
+	client, err := newSSM()
+	if err != nil {
+		return err
+	}
+	p := &ssmconfig.Provider{SSM: client}
+	diff, err := p.DiffValues(context.Background(), configPath, c, values)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return writeJSON(stdout, diff)
+	}
+	_, err = io.WriteString(stdout, diff.String())
+	return err
+}
+
+// readValues reads a JSON object of parameter values keyed by field name. Values may be
+// strings, numbers, or booleans.
+func readValues(file string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, errors.Wrapf(err, "could not decode %s", file)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[name] = v
+		case json.Number:
+			values[name] = v.String()
+		case bool:
+			values[name] = strconv.FormatBool(v)
+		default:
+			return nil, errors.Errorf("%s: the value of %s must be a string, number, or boolean", file, name)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/internal/schema"
+	"github.com/pkg/errors"
+)
+
+// envRules transform parameter names into environment variable names.
+type envRules struct {
+	prefix  string
+	letters string // "upper", "lower", or "keep"
+	replace string // characters replaced with underscores
+	names   map[string]string
+}
+
+// validEnvName matches the environment variable names that are portable to shells.
+var validEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envName returns the environment variable name for the parameter rel, which is relative
+// to the base path. Names set with -map take precedence over the other rules.
+func (r envRules) envName(rel string) (string, error) {
+	if name, ok := r.names[rel]; ok {
+		return name, nil
+	}
+
+	name := strings.Map(func(c rune) rune {
+		if strings.ContainsRune(r.replace, c) {
+			return '_'
+		}
+		return c
+	}, rel)
+	switch r.letters {
+	case "upper":
+		name = strings.ToUpper(name)
+	case "lower":
+		name = strings.ToLower(name)
+	}
+	name = r.prefix + name
+
+	if !validEnvName.MatchString(name) {
+		return "", errors.Errorf("parameter %s maps to invalid environment variable name %q; use -map to name it", rel, name)
+	}
+	return name, nil
+}
+
+// mapFlag is a flag of name=value pairs that can be repeated.
+type mapFlag map[string]string
+
+func (f mapFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f mapFlag) Set(s string) error {
+	i := strings.LastIndex(s, "=")
+	if i <= 0 || i == len(s)-1 {
+		return errors.Errorf("%q must be of the form parameter=NAME", s)
+	}
+	f[strings.TrimPrefix(s[:i], "/")] = s[i+1:]
+	return nil
+}
+
+// execCommand replaces the current process with argv, run with env. It only returns if
+// the command cannot be started, or, on platforms without exec, when the command exits.
+var execCommand = execve
+
+func runExec(fs *flag.FlagSet, args []string, _ io.Writer) error {
+	var (
+		sf         structFlags
+		dir        string
+		configPath string
+		rules      = envRules{names: mapFlag{}}
+		clearEnv   bool
+	)
+	sf.register(fs)
+	fs.StringVar(&dir, "dir", ".", "directory of the Go package containing the -type struct")
+	fs.StringVar(&configPath, "path", "", "base path of the parameters")
+	fs.StringVar(&rules.prefix, "prefix", "", "prefix added to environment variable names")
+	fs.StringVar(&rules.letters, "case", "upper", "case of environment variable names: upper, lower, or keep")
+	fs.StringVar(&rules.replace, "replace", "/-.", "characters of parameter names replaced with underscores")
+	fs.Var(mapFlag(rules.names), "map", "name the variable for a parameter relative to -path, as parameter=NAME; may be repeated")
+	fs.BoolVar(&clearEnv, "clear-env", false, "do not pass the current environment to the command")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if configPath == "" {
+		return usageError("-path must be set")
+	}
+	if fs.NArg() == 0 {
+		return usageError("a command must be given")
+	}
+	switch rules.letters {
+	case "upper", "lower", "keep":
+	default:
+		return usageError("-case must be upper, lower, or keep")
+	}
+
+	client, err := newSSM()
+	if err != nil {
+		return err
+	}
+
+	var vars map[string]string
+	if sf.typeName != "" || sf.schemaFile != "" {
+		s, err := sf.load(dir)
+		if err != nil {
+			return err
+		}
+		vars, err = structEnv(client, configPath, s, rules)
+		if err != nil {
+			return err
+		}
+	} else {
+		vars, err = pathEnv(client, configPath, rules)
+		if err != nil {
+			return err
+		}
+	}
+
+	var env []string
+	if !clearEnv {
+		for _, kv := range os.Environ() {
+			if _, ok := vars[kv[:strings.IndexByte(kv+"=", '=')]]; !ok {
+				env = append(env, kv)
+			}
+		}
+	}
+	for name, value := range vars {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+
+	return execCommand(fs.Args(), env)
+}
+
+// pathEnv returns the environment variables for every parameter under configPath.
+func pathEnv(client ssmiface.SSMAPI, configPath string, rules envRules) (map[string]string, error) {
+	vars := map[string]string{}
+	sources := map[string]string{}
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(configPath),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	}
+	var err error
+	pageErr := client.GetParametersByPathPagesWithContext(context.Background(), input, func(output *ssm.GetParametersByPathOutput, _ bool) bool {
+		for _, param := range output.Parameters {
+			name := aws.StringValue(param.Name)
+			if err = setVar(vars, sources, rules, relative(configPath, name), aws.StringValue(param.Value)); err != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if pageErr != nil {
+		return nil, errors.Wrap(pageErr, "could not get parameters by path")
+	}
+	return vars, err
+}
+
+// structEnv returns the environment variables for the fields of s, resolved as by
+// Process. Fields tagged `env:"NAME"` are named by the tag.
+func structEnv(client ssmiface.SSMAPI, configPath string, s *schema.Struct, rules envRules) (map[string]string, error) {
+	c, err := newStringConfig(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ssmconfig.Provider{SSM: client}
+	report, err := p.ProcessWithReport(context.Background(), configPath, c)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]string{}
+	sources := map[string]string{}
+	v := reflect.ValueOf(c).Elem()
+	for _, f := range report.Fields {
+		value := v.FieldByName(f.Field).String()
+		if f.Source == ssmconfig.SourceAbsent || value == "" {
+			continue
+		}
+
+		// Fields are looked up by name, as the report omits schema fields without an
+		// `ssm` tag.
+		sf, _ := v.Type().FieldByName(f.Field)
+		rel := relative(configPath, f.Parameter)
+		if name := sf.Tag.Get("env"); name != "" {
+			if _, ok := rules.names[rel]; !ok {
+				rules.names[rel] = name
+			}
+		}
+		if err := setVar(vars, sources, rules, rel, value); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// setVar sets the environment variable for the parameter rel to value. sources records
+// the parameter each variable was set from, to detect conflicts.
+func setVar(vars, sources map[string]string, rules envRules, rel, value string) error {
+	name, err := rules.envName(rel)
+	if err != nil {
+		return err
+	}
+	if other, ok := sources[name]; ok && other != rel {
+		return errors.Errorf("parameters %s and %s both map to %s; use -map to rename one", other, rel, name)
+	}
+	vars[name] = value
+	sources[name] = rel
+	return nil
+}
+
+// relative returns name relative to configPath.
+func relative(configPath, name string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(name, strings.TrimSuffix(configPath, "/")), "/")
+}
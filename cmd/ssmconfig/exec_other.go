@@ -0,0 +1,27 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// execve runs argv as a child process and waits for it to exit, as the current process
+// cannot be replaced on this platform. The exit status of the command is returned as an
+// exitError.
+func execve(argv, env []string) error {
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if eerr, ok := err.(*exec.ExitError); ok {
+		return exitError(eerr.ExitCode())
+	}
+	return errors.Wrap(err, "could not run command")
+}
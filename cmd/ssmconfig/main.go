@@ -0,0 +1,139 @@
+// Command ssmconfig inspects config structs used with ssmconfig.
+//
+// Usage:
+//
+//	ssmconfig <command> [flags] [dir]
+//
+// The commands are:
+//
+//	check   verify that a config can be loaded, reporting every problem
+//	diff    compare desired values with the parameters under a path
+//	exec    run a command with parameters as environment variables
+//	policy  print the least-privilege IAM policy needed to load a config
+//	schema  print the schema of a config struct as JSON
+//
+// Each command reads the config struct named by -type from the Go package in dir, which
+// defaults to the current directory, or from a schema file written by the schema command
+// when -schema is set. The exec command takes the directory as -dir, as its arguments are
+// the command to run, and only needs a struct to restrict the parameters it reads. Run
+// "ssmconfig <command> -h" for the flags of a command.
+//
+// Commands that read Parameter Store use the default AWS credentials and region, as
+// configured by the environment and shared config files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/pkg/errors"
+)
+
+// command is a subcommand of ssmconfig.
+type command struct {
+	name  string
+	usage string
+	run   func(fs *flag.FlagSet, args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{name: "check", usage: "check -type T -path /base [-json] [dir]", run: runCheck},
+	{name: "diff", usage: "diff -type T -path /base -values file [-json] [dir]", run: runDiff},
+	{name: "exec", usage: "exec -path /base [-type T [-dir dir]] [-prefix P] [-map param=NAME] -- command [args]", run: runExec},
+	{name: "policy", usage: "policy -type T -path /base [-path /other] [-region r] [-account id] [dir]", run: runPolicy},
+	{name: "schema", usage: "schema -type T [dir]", run: runSchema},
+}
+
+// newSSM returns the client used to read and write parameters.
+var newSSM = func() (ssmiface.SSMAPI, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create aws session")
+	}
+	return ssm.New(sess), nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command named by args[0] and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		fs := flag.NewFlagSet("ssmconfig "+cmd.name, flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "Usage: ssmconfig %s\n", cmd.usage)
+			fs.PrintDefaults()
+		}
+
+		err := cmd.run(fs, args[1:], stdout)
+		if err == flag.ErrHelp {
+			return 2
+		}
+		if code, ok := err.(exitError); ok {
+			return int(code)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "ssmconfig %s: %v\n", cmd.name, err)
+			if _, ok := errors.Cause(err).(usageError); ok {
+				fs.Usage()
+				return 2
+			}
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "ssmconfig: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "\tssmconfig %s\n", cmd.usage)
+	}
+}
+
+// usageError is an error caused by invalid arguments.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// exitError is the exit status of a command run by exec.
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// stringsFlag is a flag that can be repeated to build a list.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/internal/gentest"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+const gentestDir = "../../internal/gentest"
+
+// newServer starts an ssmtest.Server and directs the commands to it.
+func newServer(t *testing.T) *ssmtest.Server {
+	s := ssmtest.NewServer()
+	t.Cleanup(s.Close)
+
+	orig := newSSM
+	newSSM = func() (ssmiface.SSMAPI, error) { return s.Client(), nil }
+	t.Cleanup(func() { newSSM = orig })
+	return s
+}
+
+// writeFile writes data to a file in a temporary directory and returns its name.
+func writeFile(t *testing.T, name, data string) string {
+	file := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestRun_policy(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"policy", "-type", "Config", "-path", "/prod", "-region", "us-east-1", "-account", "123456789012", gentestDir}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+	}
+
+	var policy ssmconfig.IAMPolicy
+	if err := json.Unmarshal(stdout.Bytes(), &policy); err != nil {
+		t.Fatalf("run() wrote invalid JSON: %v", err)
+	}
+	if len(policy.Statement) != 2 {
+		t.Fatalf("run() want 2 statements, have %+v", policy.Statement)
+	}
+	if n := len(policy.Statement[0].Resource); n != 11 {
+		t.Errorf("run() want 11 parameters, have %d", n)
+	}
+	if stmt := policy.Statement[1]; stmt.Action[0] != "kms:Decrypt" {
+		t.Errorf("run() want kms:Decrypt for the Secret field, have %+v", stmt)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = run([]string{"policy", "-type", "Config", gentestDir}, &stdout, &stderr)
+	if code != 2 || !strings.Contains(stderr.String(), "-path must be set") {
+		t.Errorf("run() want usage error, have exit code %d: %s", code, stderr.String())
+	}
+}
+
+func TestRun_schema(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"schema", "-type", "Config", gentestDir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+	}
+
+	file := writeFile(t, "schema.json", stdout.String())
+
+	sf := structFlags{schemaFile: file}
+	s, err := sf.load(".")
+	if err != nil {
+		t.Fatalf("load() unexpected error: %v", err)
+	}
+	c, err := newConfig(s)
+	if err != nil {
+		t.Fatalf("newConfig() unexpected error: %v", err)
+	}
+
+	// The dynamic struct must resolve the same parameters as the original.
+	p := &ssmconfig.Provider{}
+	want, err := p.Policy(&gentest.Config{}, []string{"/prod"}, ssmconfig.PolicyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := p.Policy(c, []string{"/prod"}, ssmconfig.PolicyOptions{})
+	if err != nil {
+		t.Fatalf("Policy() unexpected error: %v", err)
+	}
+	wantJSON, _ := json.Marshal(want)
+	haveJSON, _ := json.Marshal(have)
+	if !bytes.Equal(wantJSON, haveJSON) {
+		t.Errorf("Policy() differs for schema file:\nwant %s\nhave %s", wantJSON, haveJSON)
+	}
+}
+
+func TestRun_diff(t *testing.T) {
+	s := newServer(t)
+	s.Put("/prod/strings/s1", "old")
+	s.Put("/prod/int/i1", "1")
+	s.PutSecure("/prod/secret/password", "old-password")
+	s.Put("/prod/unused", "unused")
+
+	values := writeFile(t, "values.json", `{"String": "new", "Int": 1, "Bool": true, "Password": "new-password"}`)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"diff", "-type", "Config", "-path", "/prod", "-values", values, gentestDir}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+	}
+
+	want := `~ /prod/strings/s1: "old" -> "new"
++ /prod/bool/b1 = "true" (String)
+~ /prod/secret/password: [REDACTED] -> [REDACTED]
+? /prod/unused: not used by any field
+`
+	if stdout.String() != want {
+		t.Errorf("run() unexpected output:\nwant %s\nhave %s", want, stdout.String())
+	}
+
+	stdout.Reset()
+	code = run([]string{"diff", "-type", "Config", "-path", "/prod", "-values", values, "-json", gentestDir}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+	}
+	var diff ssmconfig.Diff
+	if err := json.Unmarshal(stdout.Bytes(), &diff); err != nil || diff.Unchanged != 1 {
+		t.Errorf("run() unexpected JSON output (%v): %s", err, stdout.String())
+	}
+
+	stderr.Reset()
+	bad := writeFile(t, "bad.json", `{"Nope": "x"}`)
+	code = run([]string{"diff", "-type", "Config", "-path", "/prod", "-values", bad, gentestDir}, &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), "Nope is not a field") {
+		t.Errorf("run() want error for unknown field, have exit code %d: %s", code, stderr.String())
+	}
+}
+
+func TestRun_check(t *testing.T) {
+	s := newServer(t)
+	s.Put("/prod/int/i1", "1")
+	s.Put("/prod/strings/s1", "string")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-type", "Config", "-path", "/prod", gentestDir}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+	}
+	want := "warning: String (/prod/strings/s1): ssmconfig: the default value of field String is not used because /prod/strings/s1 exists\nConfig: 11 fields checked\n"
+	if stdout.String() != want {
+		t.Errorf("run() unexpected output:\nwant %q\nhave %q", want, stdout.String())
+	}
+
+	// Problems are reported for every field, from a schema file.
+	stdout.Reset()
+	run([]string{"schema", "-type", "Config", gentestDir}, &stdout, &stderr)
+	schemaFile := writeFile(t, "schema.json", stdout.String())
+
+	s.Delete("/prod/int/i1")
+	s.Put("/prod/bool/b1", "yes")
+	stdout.Reset()
+	code = run([]string{"check", "-schema", schemaFile, "-path", "/prod"}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("run() want exit code 1, have %d", code)
+	}
+	for _, line := range []string{
+		"error: Int (/prod/int/i1): ssmconfig: /prod/int/i1 is required",
+		"error: Bool (/prod/bool/b1): ssmconfig: error setting field Bool",
+	} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("run() missing %q in output:\n%s", line, stdout.String())
+		}
+	}
+	if !strings.Contains(stderr.String(), "configuration has problems") {
+		t.Errorf("run() unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestRun_check_unsupported(t *testing.T) {
+	s := newServer(t)
+	s.Put("/prod/list", "a,b")
+	s.Put("/prod/map", "a=b")
+	s.Put("/prod/nested", "x")
+	s.Put("/prod/time", "2020-01-01T00:00:00Z")
+	s.PutSecure("/prod/pin", "not-a-number")
+	s.Put("/prod/duration", "5")
+
+	schemaFile := writeFile(t, "schema.json", `{
+		"package": "config",
+		"name": "Config",
+		"fields": [
+			{"name": "List", "tag": "ssm:\"/list\"", "type": "[]string"},
+			{"name": "Map", "tag": "ssm:\"/map\"", "type": "map[string]string"},
+			{"name": "Nested", "tag": "ssm:\"/nested\"", "type": "config.Nested"},
+			{"name": "Time", "tag": "ssm:\"/time\"", "type": "time.Time"},
+			{"name": "Default", "tag": "ssm:\"/default\" default:\"x\"", "type": "time.Time"},
+			{"name": "Unset", "tag": "ssm:\"/unset\"", "type": "time.Time"},
+			{"name": "Pin", "tag": "ssm:\"/pin\"", "type": "ssmconfig.Secret[int]"},
+			{"name": "Duration", "tag": "ssm:\"/duration\"", "type": "time.Duration", "kind": "int64"}
+		]
+	}`)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-schema", schemaFile, "-path", "/prod"}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("run() want exit code 1, have %d: %s", code, stderr.String())
+	}
+	for _, line := range []string{
+		"error: List (/prod/list): ssmconfig: field List has type []string, which cannot be decoded",
+		"error: Map (/prod/map): ssmconfig: field Map has type map[string]string, which cannot be decoded",
+		"error: Nested (/prod/nested): ssmconfig: field Nested has type config.Nested, which cannot be decoded",
+		"error: Time (/prod/time): ssmconfig: field Time has type time.Time, which cannot be decoded",
+		"error: Default (/prod/default): ssmconfig: field Default has type time.Time, which cannot be decoded",
+		"error: Pin (/prod/pin): ssmconfig: error setting field Pin",
+	} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("run() missing %q in output:\n%s", line, stdout.String())
+		}
+	}
+	// Unset fields of unsupported types do not prevent Process from loading the config.
+	for _, field := range []string{"Unset", "Duration"} {
+		if strings.Contains(stdout.String(), field+" (") {
+			t.Errorf("run() unexpected problem for %s:\n%s", field, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), "not-a-number") {
+		t.Errorf("run() leaked Secret value:\n%s", stdout.String())
+	}
+}
+
+// captureExec records the command and environment passed to execCommand.
+func captureExec(t *testing.T) (argv, env *[]string) {
+	argv, env = new([]string), new([]string)
+	orig := execCommand
+	execCommand = func(a, e []string) error {
+		*argv, *env = a, e
+		return nil
+	}
+	t.Cleanup(func() { execCommand = orig })
+	return argv, env
+}
+
+// environ returns the variables in env that are not in the current environment.
+func environ(env []string) map[string]string {
+	current := map[string]bool{}
+	for _, kv := range os.Environ() {
+		current[kv] = true
+	}
+	vars := map[string]string{}
+	for _, kv := range env {
+		if !current[kv] {
+			i := strings.IndexByte(kv, '=')
+			vars[kv[:i]] = kv[i+1:]
+		}
+	}
+	return vars
+}
+
+func TestRun_exec(t *testing.T) {
+	s := newServer(t)
+	s.Put("/prod/db/host", "localhost")
+	s.PutSecure("/prod/db/password", "hunter2")
+	s.Put("/prod/feature-flags.enabled", "true")
+	s.Put("/other/x", "x")
+
+	t.Run("path", func(t *testing.T) {
+		argv, env := captureExec(t)
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"exec", "-path", "/prod", "-prefix", "APP_", "-map", "db/host=DATABASE_HOST", "--", "migrate", "up"}, &stdout, &stderr)
+		if code != 0 {
+			t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+		}
+
+		if want := []string{"migrate", "up"}; !reflect.DeepEqual(*argv, want) {
+			t.Errorf("run() want command %v, have %v", want, *argv)
+		}
+		want := map[string]string{
+			"DATABASE_HOST":             "localhost",
+			"APP_DB_PASSWORD":           "hunter2",
+			"APP_FEATURE_FLAGS_ENABLED": "true",
+		}
+		if vars := environ(*env); !reflect.DeepEqual(vars, want) {
+			t.Errorf("run() unexpected environment:\nwant %v\nhave %v", want, vars)
+		}
+		if len(*env) < len(os.Environ()) {
+			t.Errorf("run() did not pass the current environment")
+		}
+		if stdout.Len() != 0 || strings.Contains(stderr.String(), "hunter2") {
+			t.Errorf("run() unexpected output: %q %q", stdout.String(), stderr.String())
+		}
+	})
+
+	t.Run("clear env and case", func(t *testing.T) {
+		_, env := captureExec(t)
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"exec", "-path", "/prod/db", "-clear-env", "-case", "lower", "--", "env"}, &stdout, &stderr)
+		if code != 0 {
+			t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+		}
+		if want := []string{"host=localhost", "password=hunter2"}; !reflect.DeepEqual(*env, want) {
+			t.Errorf("run() want environment %v, have %v", want, *env)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		_, env := captureExec(t)
+		s.Put("/prod/int/i1", "1")
+		defer s.Delete("/prod/int/i1")
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"exec", "-path", "/prod", "-type", "Config", "-dir", gentestDir, "-clear-env", "--", "env"}, &stdout, &stderr)
+		if code != 0 {
+			t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+		}
+		// Defaults are included; parameters not used by the struct are not.
+		want := []string{"BOOL_B1=true", "INT_I1=1", "INT_I64=64", "INT_LEVEL=3", "STRINGS_S1=string"}
+		if !reflect.DeepEqual(*env, want) {
+			t.Errorf("run() want environment %v, have %v", want, *env)
+		}
+	})
+
+	t.Run("schema field without ssm tag", func(t *testing.T) {
+		_, env := captureExec(t)
+		schemaFile := writeFile(t, "schema.json", `{
+			"package": "config",
+			"name": "Config",
+			"fields": [
+				{"name": "Notes", "tag": "json:\"notes\"", "type": "string", "kind": "string"},
+				{"name": "Host", "tag": "ssm:\"/db/host\" env:\"DATABASE_HOST\"", "type": "string", "kind": "string"},
+				{"name": "Password", "tag": "ssm:\"/db/password\"", "type": "string", "kind": "string"}
+			]
+		}`)
+
+		var stdout, stderr bytes.Buffer
+		code := run([]string{"exec", "-path", "/prod", "-schema", schemaFile, "-clear-env", "--", "env"}, &stdout, &stderr)
+		if code != 0 {
+			t.Fatalf("run() want exit code 0, have %d: %s", code, stderr.String())
+		}
+		want := []string{"DATABASE_HOST=localhost", "DB_PASSWORD=hunter2"}
+		if !reflect.DeepEqual(*env, want) {
+			t.Errorf("run() want environment %v, have %v", want, *env)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		captureExec(t)
+		for _, args := range [][]string{
+			{"exec", "-path", "/prod"},
+			{"exec", "-path", "/prod", "-case", "title", "--", "env"},
+			{"exec", "-path", "/prod", "-replace", "", "--", "env"},
+			{"exec", "-path", "/prod", "-map", "db/host=X", "-map", "db/password=X", "--", "env"},
+		} {
+			var stdout, stderr bytes.Buffer
+			if code := run(args, &stdout, &stderr); code == 0 {
+				t.Errorf("run(%q) want non-zero exit code", args)
+			}
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+)
+
+func runPolicy(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+	var (
+		sf      structFlags
+		paths   stringsFlag
+		keyARNs stringsFlag
+		opts    ssmconfig.PolicyOptions
+		p       ssmconfig.Provider
+	)
+	sf.register(fs)
+	fs.Var(&paths, "path", "base path the config is processed with; may be repeated")
+	fs.StringVar(&opts.Partition, "partition", "aws", "AWS partition")
+	fs.StringVar(&opts.Region, "region", "", "AWS region; default any region")
+	fs.StringVar(&opts.Account, "account", "", "AWS account ID; default any account")
+	fs.Var(&keyARNs, "key", "ARN of a KMS key SecureString parameters are encrypted with; may be repeated")
+	fs.BoolVar(&opts.Watch, "watch", false, "grant ssm:DescribeParameters, required to watch for changes")
+	fs.BoolVar(&p.DisableDecryption, "disable-decryption", false, "request fields without decryption unless tagged decrypt:\"true\"")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return usageError("-path must be set")
+	}
+	opts.KeyARNs = keyARNs
+
+	s, err := sf.load(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	c, err := newConfig(s)
+	if err != nil {
+		return err
+	}
+
+	policy, err := p.Policy(c, paths, opts)
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, policy)
+}
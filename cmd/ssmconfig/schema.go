@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/internal/schema"
+	"github.com/pkg/errors"
+)
+
+// structFlags are the flags used to select a config struct.
+type structFlags struct {
+	typeName   string
+	schemaFile string
+}
+
+func (f *structFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.typeName, "type", "", "name of the config struct type")
+	fs.StringVar(&f.schemaFile, "schema", "", "schema file written by the schema command, used in place of -type")
+}
+
+// load returns the schema of the selected struct in the package in dir.
+func (f *structFlags) load(dir string) (*schema.Struct, error) {
+	switch {
+	case f.schemaFile != "" && f.typeName != "":
+		return nil, usageError("-type and -schema cannot both be set")
+	case f.schemaFile != "":
+		b, err := ioutil.ReadFile(f.schemaFile)
+		if err != nil {
+			return nil, err
+		}
+		var s schema.Struct
+		if err := json.Unmarshal(b, &s); err != nil {
+			return nil, errors.Wrapf(err, "could not decode %s", f.schemaFile)
+		}
+		return &s, nil
+	case f.typeName != "":
+		structs, err := schema.Load(dir, f.typeName)
+		if err != nil {
+			return nil, err
+		}
+		return structs[0], nil
+	default:
+		return nil, usageError("-type or -schema must be set")
+	}
+}
+
+// newConfig returns a pointer to a new value of a struct type equivalent to s, which can
+// be passed to a Provider in place of the original struct.
+//
+// Each field has the tags of the original field and a type with the same decoding
+// behavior: its basic kind, []byte, or a Secret of one of those. Fields of any other type,
+// or whose type could not be determined, have a type that no value can be decoded into;
+// see unsupportedFields.
+func newConfig(s *schema.Struct) (interface{}, error) {
+	fields := make([]reflect.StructField, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		if !f.Exported() {
+			return nil, errors.Errorf("%s.%s: fields with an ssm tag must be exported", s.Name, f.Name)
+		}
+		t, _ := fieldType(f)
+		fields = append(fields, reflect.StructField{
+			Name: f.Name,
+			Type: t,
+			Tag:  reflect.StructTag(f.Tag),
+		})
+	}
+	return reflect.New(reflect.StructOf(fields)).Interface(), nil
+}
+
+// newStringConfig is the same as newConfig, except that every field is a string, so that
+// it is set to the raw value of its parameter or its default value.
+func newStringConfig(s *schema.Struct) (interface{}, error) {
+	fields := make([]reflect.StructField, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		if !f.Exported() {
+			return nil, errors.Errorf("%s.%s: fields with an ssm tag must be exported", s.Name, f.Name)
+		}
+		fields = append(fields, reflect.StructField{
+			Name: f.Name,
+			Type: basicTypes["string"],
+			Tag:  reflect.StructTag(f.Tag),
+		})
+	}
+	return reflect.New(reflect.StructOf(fields)).Interface(), nil
+}
+
+// basicTypes maps the names of basic kinds to their types.
+var basicTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []interface{}{
+		"", false,
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0), uintptr(0),
+		float32(0), float64(0), complex64(0), complex128(0),
+	} {
+		t := reflect.TypeOf(v)
+		basicTypes[t.Kind().String()] = t
+	}
+}
+
+// secretTypes maps the type arguments of the Secrets that can be decoded to their types.
+var secretTypes = map[string]reflect.Type{
+	"string":  reflect.TypeOf(ssmconfig.Secret[string]{}),
+	"bool":    reflect.TypeOf(ssmconfig.Secret[bool]{}),
+	"int":     reflect.TypeOf(ssmconfig.Secret[int]{}),
+	"int8":    reflect.TypeOf(ssmconfig.Secret[int8]{}),
+	"int16":   reflect.TypeOf(ssmconfig.Secret[int16]{}),
+	"int32":   reflect.TypeOf(ssmconfig.Secret[int32]{}),
+	"int64":   reflect.TypeOf(ssmconfig.Secret[int64]{}),
+	"float32": reflect.TypeOf(ssmconfig.Secret[float32]{}),
+	"float64": reflect.TypeOf(ssmconfig.Secret[float64]{}),
+	"[]byte":  reflect.TypeOf(ssmconfig.Secret[[]byte]{}),
+	"[]uint8": reflect.TypeOf(ssmconfig.Secret[[]byte]{}),
+}
+
+// unsupportedType is the type of fields that ssmconfig cannot decode. Decoding any value
+// into it fails, as it would for the original field.
+var unsupportedType = reflect.TypeOf([]struct{}(nil))
+
+// fieldType returns the type of f in the struct returned by newConfig, and whether it
+// can be decoded.
+func fieldType(f schema.Field) (reflect.Type, bool) {
+	if strings.HasPrefix(f.Type, "ssmconfig.Secret[") && strings.HasSuffix(f.Type, "]") {
+		arg := strings.TrimSuffix(strings.TrimPrefix(f.Type, "ssmconfig.Secret["), "]")
+		if t, ok := secretTypes[arg]; ok {
+			return t, true
+		}
+		return unsupportedType, false
+	}
+	if f.Type == "[]byte" || f.Type == "[]uint8" {
+		return reflect.TypeOf([]byte(nil)), true
+	}
+	if t, ok := basicTypes[f.Kind]; ok {
+		return t, true
+	}
+	return unsupportedType, false
+}
+
+// unsupportedFields returns the types of the fields of s that ssmconfig cannot decode,
+// keyed by field name.
+func unsupportedFields(s *schema.Struct) map[string]string {
+	fields := map[string]string{}
+	for _, f := range s.Fields {
+		if _, ok := fieldType(f); !ok {
+			fields[f.Name] = f.Type
+		}
+	}
+	return fields
+}
+
+func runSchema(fs *flag.FlagSet, args []string, stdout io.Writer) error {
+	var sf structFlags
+	sf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if sf.schemaFile != "" {
+		return usageError("-schema cannot be used with the schema command")
+	}
+
+	s, err := sf.load(dirArg(fs))
+	if err != nil {
+		return err
+	}
+	return writeJSON(stdout, s)
+}
+
+// dirArg returns the package directory argument, or the current directory.
+func dirArg(fs *flag.FlagSet) string {
+	if fs.NArg() > 0 {
+		return fs.Arg(0)
+	}
+	return "."
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
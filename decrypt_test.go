@@ -0,0 +1,110 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_Process_decrypt(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/plain", "plain")
+	s.PutSecure("/base/secret", "hunter2")
+	s.DenyDecryption(true)
+
+	secret, _ := s.Get("/base/secret")
+	ciphertext := ssmtest.Ciphertext(secret)
+
+	t.Run("decrypt tag", func(t *testing.T) {
+		type config struct {
+			Plain  string `ssm:"/plain" decrypt:"false"`
+			Secret string `ssm:"/secret" decrypt:"false"`
+		}
+
+		p := &ssmconfig.Provider{SSM: s.Client()}
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c.Plain != "plain" || c.Secret != ciphertext {
+			t.Errorf("Process() unexpected values: %+v", c)
+		}
+
+		type decryptedConfig struct {
+			Plain  string `ssm:"/plain" decrypt:"false"`
+			Secret string `ssm:"/secret"`
+		}
+		var dc decryptedConfig
+		if err := p.Process("/base", &dc); err == nil {
+			t.Errorf("Process() expected error decrypting without kms:Decrypt")
+		}
+	})
+
+	t.Run("disable decryption", func(t *testing.T) {
+		type config struct {
+			Plain  string `ssm:"/plain"`
+			Secret string `ssm:"/secret"`
+		}
+
+		p := &ssmconfig.Provider{SSM: s.Client(), DisableDecryption: true}
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c.Plain != "plain" || c.Secret != ciphertext {
+			t.Errorf("Process() unexpected values: %+v", c)
+		}
+	})
+
+	t.Run("split requests", func(t *testing.T) {
+		s.DenyDecryption(false)
+		defer s.DenyDecryption(true)
+
+		type config struct {
+			Plain  string `ssm:"/plain"`
+			Secret string `ssm:"/secret" decrypt:"true"`
+		}
+
+		p := &ssmconfig.Provider{SSM: s.Client(), DisableDecryption: true}
+		calls := s.Calls("GetParameters")
+		report, err := p.ProcessWithReport(context.Background(), "/base", &config{})
+		if err != nil {
+			t.Fatalf("ProcessWithReport() unexpected error: %v", err)
+		}
+		if n := s.Calls("GetParameters") - calls; n != 2 {
+			t.Errorf("Process() want 2 requests, have %d", n)
+		}
+		if report.Requests != 2 {
+			t.Errorf("Report.Requests want 2, have %d", report.Requests)
+		}
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		s.DenyDecryption(false)
+		defer s.DenyDecryption(true)
+
+		type encrypted struct {
+			Secret string `ssm:"/secret" decrypt:"false"`
+		}
+		type decrypted struct {
+			Secret string `ssm:"/secret"`
+		}
+
+		p := &ssmconfig.Provider{SSM: s.Client(), Cache: ssmconfig.NewCache(time.Minute)}
+		var e encrypted
+		var d decrypted
+		if err := p.Process("/base", &e); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if err := p.Process("/base", &d); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if e.Secret != ciphertext || d.Secret != "hunter2" {
+			t.Errorf("Process() cached values shared across decryption settings: %q, %q", e.Secret, d.Secret)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package ssmconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// DiffKind is the kind of a DiffEntry.
+type DiffKind int
+
+const (
+	// DiffAdd is a parameter that does not exist and would be created.
+	DiffAdd DiffKind = iota
+
+	// DiffChange is a parameter whose value or type would change.
+	DiffChange
+
+	// DiffMissing is the parameter of a required field that does not exist and has no
+	// desired value.
+	DiffMissing
+
+	// DiffOrphan is a parameter under the config path that is not used by any field.
+	DiffOrphan
+)
+
+// String returns the name of k, e.g. "add".
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdd:
+		return "add"
+	case DiffChange:
+		return "change"
+	case DiffMissing:
+		return "missing"
+	case DiffOrphan:
+		return "orphan"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes k as its name.
+func (k DiffKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a DiffKind from its name.
+func (k *DiffKind) UnmarshalText(text []byte) error {
+	for _, kind := range []DiffKind{DiffAdd, DiffChange, DiffMissing, DiffOrphan} {
+		if string(text) == kind.String() {
+			*k = kind
+			return nil
+		}
+	}
+	return errors.Errorf("ssmconfig: unknown diff kind %q", text)
+}
+
+// DiffEntry describes a difference between a config and Parameter Store.
+//
+// Values of SecureString parameters, and of fields that are secure, sensitive, or a
+// Secret, are replaced with "[REDACTED]". A desired value for an existing SecureString
+// parameter is a SecureString, as Store keeps the parameter encrypted.
+type DiffEntry struct {
+	Kind DiffKind `json:"kind"`
+
+	// Field is the name of the struct field. It is empty for orphaned parameters.
+	Field string `json:"field,omitempty"`
+
+	// Parameter is the name of the parameter.
+	Parameter string `json:"parameter"`
+
+	// OldType and OldValue describe the current parameter, if it exists.
+	OldType  string `json:"oldType,omitempty"`
+	OldValue string `json:"oldValue,omitempty"`
+
+	// Type and Value describe the desired parameter, if there is a desired value.
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+func (e DiffEntry) String() string {
+	switch e.Kind {
+	case DiffAdd:
+		return fmt.Sprintf("+ %s = %s (%s)", e.Parameter, quote(e.Value), e.Type)
+	case DiffChange:
+		s := fmt.Sprintf("~ %s: %s -> %s", e.Parameter, quote(e.OldValue), quote(e.Value))
+		if e.OldType != e.Type {
+			s += fmt.Sprintf(" (%s -> %s)", e.OldType, e.Type)
+		}
+		return s
+	case DiffMissing:
+		return fmt.Sprintf("! %s: required by field %s, but does not exist", e.Parameter, e.Field)
+	default:
+		return fmt.Sprintf("? %s: not used by any field", e.Parameter)
+	}
+}
+
+func quote(value string) string {
+	if value == redacted {
+		return value
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// Diff is the difference between a config and the parameters under its config path.
+type Diff struct {
+	// ConfigPath is the base path the config was compared with.
+	ConfigPath string `json:"configPath"`
+
+	// Entries are the differences: the fields of the config in field order, followed by
+	// orphaned parameters sorted by name.
+	Entries []DiffEntry `json:"entries"`
+
+	// Unchanged is the number of fields whose parameters match their desired values.
+	Unchanged int `json:"unchanged"`
+}
+
+// Empty reports whether d has no entries.
+func (d *Diff) Empty() bool {
+	return len(d.Entries) == 0
+}
+
+// String returns d with one entry per line, e.g.
+//
+//	~ /base/changed: "old" -> "new"
+//	+ /base/new = "value" (String)
+//	! /base/required: required by field Required, but does not exist
+//	? /base/unused: not used by any field
+func (d *Diff) String() string {
+	var b strings.Builder
+	for _, e := range d.Entries {
+		b.WriteString(e.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Diff compares the fields of c with the parameters under configPath, as a plan of what
+// Store would change. Fields are encoded as by Store; fields with an empty value have no
+// desired value. c must be a pointer to a struct.
+//
+// configPath must begin with "/", as the parameters under it are read with
+// GetParametersByPath. They are read with decryption unless no field is decrypted by p;
+// SecureString values that are not decrypted are only compared by type.
+func (p *Provider) Diff(ctx context.Context, configPath string, c interface{}) (*Diff, error) {
+	v, err := structValue(c)
+	if err != nil {
+		return nil, err
+	}
+	spec := buildStructSpec(configPath, v.Type())
+
+	values := make(map[string]string, len(spec))
+	for _, field := range spec {
+		value, err := encodeValue(v.Field(field.index))
+		if err != nil {
+			return nil, errors.Wrapf(err, "ssmconfig: error encoding field %s", field.field)
+		}
+		if value != "" {
+			values[field.field] = value
+		}
+	}
+	return p.diff(ctx, configPath, spec, values)
+}
+
+// DiffValues is the same as Diff, except that the desired values are given by values,
+// keyed by field name. Values are the raw parameter values, as Process would decode them.
+// Only the type of c is used.
+func (p *Provider) DiffValues(ctx context.Context, configPath string, c interface{}, values map[string]string) (*Diff, error) {
+	v, err := structValue(c)
+	if err != nil {
+		return nil, err
+	}
+	spec := buildStructSpec(configPath, v.Type())
+
+	fields := make(map[string]struct{}, len(spec))
+	for _, field := range spec {
+		fields[field.field] = struct{}{}
+	}
+	for name := range values {
+		if _, ok := fields[name]; !ok {
+			return nil, errors.Errorf("ssmconfig: %s is not a field with an `ssm` tag", name)
+		}
+	}
+	return p.diff(ctx, configPath, spec, values)
+}
+
+func (p *Provider) diff(ctx context.Context, configPath string, spec structSpec, values map[string]string) (*Diff, error) {
+	if !strings.HasPrefix(configPath, "/") {
+		return nil, errors.Errorf("ssmconfig: config path %q must begin with /", configPath)
+	}
+
+	decrypt := false
+	for _, field := range spec {
+		decrypt = decrypt || p.decrypts(field.fieldPlan)
+	}
+	current, err := p.getParametersByPath(ctx, configPath, decrypt)
+	if err != nil {
+		return nil, errors.Wrap(err, "ssmconfig: could not get parameters by path")
+	}
+
+	d := &Diff{ConfigPath: configPath}
+	used := make(map[string]struct{}, len(spec))
+	for _, field := range spec {
+		used[field.name] = struct{}{}
+		e := DiffEntry{Field: field.field, Parameter: field.name}
+
+		param, exists := current[field.name]
+		if exists {
+			e.OldType, e.OldValue = aws.StringValue(param.Type), aws.StringValue(param.Value)
+			if e.OldType == ssm.ParameterTypeSecureString || field.secure || field.sensitive {
+				e.OldValue = redacted
+			}
+		}
+
+		// Store keeps existing SecureString parameters encrypted, so the desired value is
+		// masked whenever either value is secret.
+		value, ok := values[field.field]
+		if ok {
+			e.Type, e.Value = ssm.ParameterTypeString, value
+			if e.OldType == ssm.ParameterTypeSecureString || field.secure || field.sensitive {
+				e.Type, e.Value = ssm.ParameterTypeSecureString, redacted
+			}
+		}
+
+		switch {
+		case !ok && !exists:
+			if !field.required {
+				continue
+			}
+			e.Kind = DiffMissing
+		case !ok:
+			// Without a desired value the parameter is left unchanged.
+			d.Unchanged++
+			continue
+		case !exists:
+			e.Kind = DiffAdd
+		default:
+			e.Kind = DiffChange
+			sameValue := aws.StringValue(param.Value) == value
+			if e.OldType == ssm.ParameterTypeSecureString && !decrypt {
+				sameValue = true // the value is encrypted, so cannot be compared
+			}
+			if sameValue && e.OldType == e.Type {
+				d.Unchanged++
+				continue
+			}
+		}
+		d.Entries = append(d.Entries, e)
+	}
+
+	var orphans []string
+	for name := range current {
+		if _, ok := used[name]; !ok {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	for _, name := range orphans {
+		d.Entries = append(d.Entries, DiffEntry{
+			Kind:      DiffOrphan,
+			Parameter: name,
+			OldType:   aws.StringValue(current[name].Type),
+		})
+	}
+	return d, nil
+}
+
+// getParametersByPath returns all of the parameters under path, keyed by name.
+func (p *Provider) getParametersByPath(ctx context.Context, path string, decrypt bool) (map[string]*ssm.Parameter, error) {
+	params := map[string]*ssm.Parameter{}
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(decrypt),
+	}
+	for {
+		var output *ssm.GetParametersByPathOutput
+		_, err := p.Retry.do(ctx, func() error {
+			if err := p.wait(ctx); err != nil {
+				return err
+			}
+
+			var err error
+			output, err = p.SSM.GetParametersByPathWithContext(ctx, input)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, param := range output.Parameters {
+			params[aws.StringValue(param.Name)] = param
+		}
+		if aws.StringValue(output.NextToken) == "" {
+			return params, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
@@ -0,0 +1,162 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+type diffConfig struct {
+	Same     string                   `ssm:"/same"`
+	Changed  int                      `ssm:"/changed"`
+	Added    string                   `ssm:"/added"`
+	Retyped  string                   `ssm:"/retyped" secure:"true"`
+	Password ssmconfig.Secret[string] `ssm:"/password"`
+	Required string                   `ssm:"/required" required:"true"`
+	Unset    string                   `ssm:"/unset"`
+}
+
+func TestProvider_Diff(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/same", "same")
+	s.Put("/base/changed", "1")
+	s.Put("/base/retyped", "plain")
+	s.PutSecure("/base/password", "old-password")
+	s.Put("/base/orphan/b", "b")
+	s.Put("/base/orphan/a", "a")
+	s.Put("/other/same", "same")
+
+	c := diffConfig{
+		Same:     "same",
+		Changed:  2,
+		Added:    "added",
+		Retyped:  "plain",
+		Password: ssmconfig.NewSecret("new-password"),
+	}
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	diff, err := p.Diff(context.Background(), "/base", &c)
+	if err != nil {
+		t.Fatalf("Diff() unexpected error: %v", err)
+	}
+
+	want := &ssmconfig.Diff{
+		ConfigPath: "/base",
+		Entries: []ssmconfig.DiffEntry{
+			{Kind: ssmconfig.DiffChange, Field: "Changed", Parameter: "/base/changed", OldType: "String", OldValue: "1", Type: "String", Value: "2"},
+			{Kind: ssmconfig.DiffAdd, Field: "Added", Parameter: "/base/added", Type: "String", Value: "added"},
+			{Kind: ssmconfig.DiffChange, Field: "Retyped", Parameter: "/base/retyped", OldType: "String", OldValue: "[REDACTED]", Type: "SecureString", Value: "[REDACTED]"},
+			{Kind: ssmconfig.DiffChange, Field: "Password", Parameter: "/base/password", OldType: "SecureString", OldValue: "[REDACTED]", Type: "SecureString", Value: "[REDACTED]"},
+			{Kind: ssmconfig.DiffMissing, Field: "Required", Parameter: "/base/required"},
+			{Kind: ssmconfig.DiffOrphan, Parameter: "/base/orphan/a", OldType: "String"},
+			{Kind: ssmconfig.DiffOrphan, Parameter: "/base/orphan/b", OldType: "String"},
+		},
+		Unchanged: 1,
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Diff() unexpected diff:\nwant %+v\nhave %+v", want, diff)
+	}
+
+	text := diff.String()
+	for _, line := range []string{
+		`~ /base/changed: "1" -> "2"`,
+		`+ /base/added = "added" (String)`,
+		`~ /base/retyped: [REDACTED] -> [REDACTED] (String -> SecureString)`,
+		`! /base/required: required by field Required, but does not exist`,
+		`? /base/orphan/a: not used by any field`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("String() missing line %q in:\n%s", line, text)
+		}
+	}
+	if strings.Contains(text, "new-password") || strings.Contains(text, "old-password") {
+		t.Errorf("String() exposed a secret value:\n%s", text)
+	}
+
+	// Storing the config leaves only the entries Store does not resolve.
+	if _, err := p.Store(context.Background(), "/base", &c, ssmconfig.StoreOptions{Existing: ssmconfig.OverwriteExisting}); err != nil {
+		t.Fatalf("Store() unexpected error: %v", err)
+	}
+	diff, err = p.Diff(context.Background(), "/base", &c)
+	if err != nil {
+		t.Fatalf("Diff() unexpected error: %v", err)
+	}
+	if len(diff.Entries) != 3 || diff.Unchanged != 5 {
+		t.Errorf("Diff() after Store() want missing and orphan entries only, have %+v", diff)
+	}
+}
+
+func TestProvider_Diff_existingSecureString(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.PutSecure("/base/db/password", "old-secret")
+	p := &ssmconfig.Provider{SSM: s.Client()}
+
+	c := struct {
+		Password string `ssm:"/db/password"`
+	}{Password: "new-secret"}
+	diff, err := p.Diff(context.Background(), "/base", &c)
+	if err != nil {
+		t.Fatalf("Diff() unexpected error: %v", err)
+	}
+	want := []ssmconfig.DiffEntry{{
+		Kind:      ssmconfig.DiffChange,
+		Field:     "Password",
+		Parameter: "/base/db/password",
+		OldType:   "SecureString",
+		OldValue:  "[REDACTED]",
+		Type:      "SecureString",
+		Value:     "[REDACTED]",
+	}}
+	if !reflect.DeepEqual(diff.Entries, want) {
+		t.Errorf("Diff() unexpected entries: %+v", diff.Entries)
+	}
+	if have := diff.String(); have != "~ /base/db/password: [REDACTED] -> [REDACTED]\n" {
+		t.Errorf("Diff() unexpected output: %q", have)
+	}
+
+	c.Password = "old-secret"
+	if diff, err = p.Diff(context.Background(), "/base", &c); err != nil {
+		t.Fatalf("Diff() unexpected error: %v", err)
+	}
+	if !diff.Empty() || diff.Unchanged != 1 {
+		t.Errorf("Diff() want no changes, have %+v", diff)
+	}
+}
+
+func TestProvider_DiffValues(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/same", "same")
+	s.Put("/base/changed", "1")
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	diff, err := p.DiffValues(context.Background(), "/base", &diffConfig{}, map[string]string{
+		"Same":     "same",
+		"Changed":  "1",
+		"Required": "value",
+	})
+	if err != nil {
+		t.Fatalf("DiffValues() unexpected error: %v", err)
+	}
+	want := []ssmconfig.DiffEntry{
+		{Kind: ssmconfig.DiffAdd, Field: "Required", Parameter: "/base/required", Type: "String", Value: "value"},
+	}
+	if !reflect.DeepEqual(diff.Entries, want) || diff.Unchanged != 2 {
+		t.Errorf("DiffValues() unexpected diff: %+v", diff)
+	}
+
+	_, err = p.DiffValues(context.Background(), "/base", &diffConfig{}, map[string]string{"Nope": "x"})
+	if err == nil {
+		t.Errorf("DiffValues() expected error for unknown field")
+	}
+	_, err = p.DiffValues(context.Background(), "base", &diffConfig{}, nil)
+	if err == nil {
+		t.Errorf("DiffValues() expected error for relative config path")
+	}
+}
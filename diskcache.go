@@ -0,0 +1,259 @@
+package ssmconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/ianlopshire/go-ssm-config/internal/seal"
+)
+
+// DiskCache is a persistent cache of the last parameters successfully fetched from
+// Parameter Store. When a Provider cannot reach Parameter Store it falls back to the
+// values in the cache and returns a *StaleError describing how old they are.
+//
+// Only throttling, transient, and transport errors cause a fallback. Errors such as
+// AccessDeniedException or ValidationException, and the caller's context being canceled
+// or exceeding its deadline, are returned as is.
+//
+// SecureString values are encrypted with Key before they are written. If Key is nil
+// SecureString parameters are not cached, and a fallback that requires them will fail.
+//
+// Errors writing the cache are ignored so that they never cause a successful fetch to
+// fail.
+type DiskCache struct {
+	// Path is the file the cache is stored in.
+	Path string
+
+	// Key is used to encrypt SecureString values. It must be 16, 24, or 32 bytes long.
+	Key []byte
+
+	// Now returns the current time. If it is nil time.Now is used.
+	Now func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewDiskCache returns a cache stored in the file at path that encrypts SecureString
+// values with key.
+func NewDiskCache(path string, key []byte) *DiskCache {
+	return &DiskCache{Path: path, Key: key}
+}
+
+// StaleError is returned when parameters could not be fetched from Parameter Store and
+// values from a DiskCache were used instead. The config is fully populated when a
+// StaleError is returned.
+type StaleError struct {
+	// Err is the error that occurred fetching parameters.
+	Err error
+
+	// SavedAt is when the oldest of the values used was fetched.
+	SavedAt time.Time
+
+	// Age is how old the oldest of the values used was.
+	Age time.Duration
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf(
+		"ssmconfig: using last-known-good values up to %s old (saved %s): %v",
+		e.Age.Round(time.Second), e.SavedAt.Format(time.RFC3339), e.Err,
+	)
+}
+
+// Cause returns the error that occurred fetching parameters.
+func (e *StaleError) Cause() error { return e.Err }
+
+// Unwrap returns the error that occurred fetching parameters.
+func (e *StaleError) Unwrap() error { return e.Err }
+
+type diskCacheFile struct {
+	Parameters map[string]diskCacheEntry `json:"parameters"`
+}
+
+type diskCacheEntry struct {
+	Invalid          bool       `json:"invalid,omitempty"`
+	Type             string     `json:"type,omitempty"`
+	Value            string     `json:"value,omitempty"`
+	Sealed           bool       `json:"sealed,omitempty"`
+	Version          int64      `json:"version,omitempty"`
+	LastModifiedDate *time.Time `json:"lastModifiedDate,omitempty"`
+	ARN              string     `json:"arn,omitempty"`
+	SavedAt          time.Time  `json:"savedAt"`
+}
+
+func (c *DiskCache) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+func (c *DiskCache) read() (diskCacheFile, error) {
+	f := diskCacheFile{Parameters: map[string]diskCacheEntry{}}
+
+	b, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+
+	err = json.Unmarshal(b, &f)
+	if f.Parameters == nil {
+		f.Parameters = map[string]diskCacheEntry{}
+	}
+	return f, err
+}
+
+// save merges the parameters returned by Parameter Store into the cache file. If they
+// were fetched without decryption SecureString parameters are not saved, so that their
+// encrypted values do not replace decrypted ones.
+func (c *DiskCache) save(params []*ssm.Parameter, invalidParams []*string, decrypt bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, err := c.read()
+	if err != nil {
+		// A corrupt cache is replaced rather than preventing new values being saved.
+		f = diskCacheFile{Parameters: map[string]diskCacheEntry{}}
+	}
+
+	now := c.now()
+	for _, param := range params {
+		entry := diskCacheEntry{
+			Type:             aws.StringValue(param.Type),
+			Value:            aws.StringValue(param.Value),
+			Version:          aws.Int64Value(param.Version),
+			LastModifiedDate: param.LastModifiedDate,
+			ARN:              aws.StringValue(param.ARN),
+			SavedAt:          now,
+		}
+
+		if entry.Type == ssm.ParameterTypeSecureString {
+			if !decrypt {
+				continue
+			}
+			if c.Key == nil {
+				delete(f.Parameters, aws.StringValue(param.Name))
+				continue
+			}
+			if entry.Value, err = seal.Seal(c.Key, entry.Value); err != nil {
+				continue
+			}
+			entry.Sealed = true
+		}
+
+		f.Parameters[aws.StringValue(param.Name)] = entry
+	}
+	for _, name := range invalidParams {
+		f.Parameters[aws.StringValue(name)] = diskCacheEntry{Invalid: true, SavedAt: now}
+	}
+
+	_ = c.write(f)
+}
+
+// write atomically replaces the cache file.
+func (c *DiskCache) write(f diskCacheFile) error {
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(c.Path), filepath.Base(c.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.Path)
+}
+
+// canFallback reports whether the values in a DiskCache may be used in place of a fetch
+// that failed with err: Parameter Store is unavailable rather than rejecting the request,
+// and ctx is not done.
+func canFallback(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || canceled(err) {
+		return false
+	}
+	if rerr, ok := err.(*RetryError); ok {
+		err = rerr.Err
+	}
+	return retryable(err)
+}
+
+// fallback adds the cached values of names to params and invalidParams. If every name is
+// cached a *StaleError wrapping fetchErr is returned, otherwise fetchErr is returned.
+//
+// Only decrypted values of SecureString parameters are saved, so when decrypt is false
+// they are treated as not cached rather than returning plaintext in place of ciphertext.
+func (c *DiskCache) fallback(
+	names []string,
+	decrypt bool,
+	params map[string]*ssm.Parameter,
+	invalidParams map[string]struct{},
+	fetchErr error,
+) (map[string]*ssm.Parameter, map[string]struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, err := c.read()
+	if err != nil {
+		return nil, nil, fetchErr
+	}
+
+	var savedAt time.Time
+	for _, name := range names {
+		entry, ok := f.Parameters[name]
+		if !ok || (!decrypt && entry.Type == ssm.ParameterTypeSecureString) {
+			return nil, nil, fetchErr
+		}
+		if savedAt.IsZero() || entry.SavedAt.Before(savedAt) {
+			savedAt = entry.SavedAt
+		}
+
+		if entry.Invalid {
+			invalidParams[name] = struct{}{}
+			continue
+		}
+
+		if entry.Sealed {
+			if c.Key == nil {
+				return nil, nil, fetchErr
+			}
+			if entry.Value, err = seal.Open(c.Key, entry.Value); err != nil {
+				return nil, nil, fetchErr
+			}
+		}
+
+		params[name] = &ssm.Parameter{
+			Name:             aws.String(name),
+			Type:             aws.String(entry.Type),
+			Value:            aws.String(entry.Value),
+			Version:          aws.Int64(entry.Version),
+			LastModifiedDate: entry.LastModifiedDate,
+			ARN:              aws.String(entry.ARN),
+		}
+	}
+
+	return params, invalidParams, &StaleError{
+		Err:     fetchErr,
+		SavedAt: savedAt,
+		Age:     c.now().Sub(savedAt),
+	}
+}
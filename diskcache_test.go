@@ -0,0 +1,173 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_Process_lastKnownGood(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssmconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := ssmconfig.NewDiskCache(filepath.Join(dir, "config.json"), []byte("0123456789abcdef"))
+	cache.Now = func() time.Time { return now }
+
+	s := ssmtest.NewServer()
+	s.Put("/base/strings/s1", "string1")
+	s.PutSecure("/base/strings/secret", "hunter2")
+	p := &ssmconfig.Provider{SSM: s.Client(), LastKnownGood: cache}
+
+	type config struct {
+		S1     string `ssm:"/strings/s1"`
+		S2     string `ssm:"/strings/s2" default:"default"`
+		Secret string `ssm:"/strings/secret" required:"true"`
+	}
+	want := config{S1: "string1", S2: "default", Secret: "hunter2"}
+
+	var c config
+	if err := p.Process("/base", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(cache.Path)
+	if err != nil {
+		t.Fatalf("Process() did not write cache: %v", err)
+	}
+	if strings.Contains(string(b), "hunter2") {
+		t.Errorf("Process() wrote SecureString value to cache in plaintext")
+	}
+
+	// Parameter Store becomes unreachable.
+	s.Close()
+	now = now.Add(time.Hour)
+
+	c = config{}
+	err = p.Process("/base", &c)
+	stale, ok := err.(*ssmconfig.StaleError)
+	if !ok {
+		t.Fatalf("Process() want *StaleError, have %v", err)
+	}
+	if stale.Age != time.Hour {
+		t.Errorf("Process() unexpected stale age: want %v, have %v", time.Hour, stale.Age)
+	}
+	if stale.Err == nil {
+		t.Errorf("Process() StaleError missing cause")
+	}
+	if c != want {
+		t.Errorf("Process() want %+v, have %+v", want, c)
+	}
+
+	// Parameters that were never fetched cannot be served from the cache.
+	var other struct {
+		S3 string `ssm:"/strings/s3"`
+	}
+	err = p.Process("/base", &other)
+	if err == nil {
+		t.Fatalf("Process() expected error")
+	}
+	if _, ok := err.(*ssmconfig.StaleError); ok {
+		t.Errorf("Process() unexpected *StaleError for uncached parameters")
+	}
+
+	// The cache cannot be used without the key.
+	p.LastKnownGood = ssmconfig.NewDiskCache(cache.Path, nil)
+	if err := p.Process("/base", &c); err == nil {
+		t.Fatalf("Process() expected error")
+	} else if _, ok := err.(*ssmconfig.StaleError); ok {
+		t.Errorf("Process() unexpected *StaleError without key")
+	}
+}
+
+func TestProvider_Process_lastKnownGoodErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssmconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.PutSecure("/base/strings/secret", "hunter2")
+	p := &ssmconfig.Provider{
+		SSM:           s.Client(),
+		LastKnownGood: ssmconfig.NewDiskCache(filepath.Join(dir, "config.json"), []byte("0123456789abcdef")),
+	}
+
+	type config struct {
+		S1     string `ssm:"/strings/s1"`
+		Secret string `ssm:"/strings/secret"`
+	}
+	var c config
+	if err := p.Process("/base", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	t.Run("unavailable", func(t *testing.T) {
+		s.Fail("GetParameters", "InternalServerError", 1)
+		c := config{}
+		if _, ok := p.Process("/base", &c).(*ssmconfig.StaleError); !ok {
+			t.Fatalf("Process() want *StaleError")
+		}
+		if c.S1 != "string1" {
+			t.Errorf("Process() unexpected value: %q", c.S1)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		s.Fail("GetParameters", "AccessDeniedException", 1)
+		c := config{}
+		err := p.Process("/base", &c)
+		if err == nil {
+			t.Fatalf("Process() expected error")
+		}
+		if _, ok := err.(*ssmconfig.StaleError); ok {
+			t.Errorf("Process() unexpected *StaleError: %v", err)
+		}
+		if !strings.Contains(err.Error(), "AccessDeniedException") {
+			t.Errorf("Process() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c := config{}
+		err := p.ProcessWithContext(ctx, "/base", &c)
+		if err == nil {
+			t.Fatalf("ProcessWithContext() expected error")
+		}
+		if _, ok := err.(*ssmconfig.StaleError); ok {
+			t.Errorf("ProcessWithContext() unexpected *StaleError: %v", err)
+		}
+	})
+
+	t.Run("not decrypted", func(t *testing.T) {
+		s.Fail("GetParameters", "InternalServerError", 1)
+		var c struct {
+			Secret string `ssm:"/strings/secret" decrypt:"false"`
+		}
+		err := p.Process("/base", &c)
+		if err == nil {
+			t.Fatalf("Process() expected error")
+		}
+		if _, ok := err.(*ssmconfig.StaleError); ok {
+			t.Errorf("Process() unexpected *StaleError: %v", err)
+		}
+		if c.Secret == "hunter2" {
+			t.Errorf("Process() used decrypted value for field tagged decrypt:\"false\"")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package ssmconfig_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -127,3 +128,34 @@ func ExampleProvider_Process() {
 		log.Fatal(err.Error())
 	}
 }
+
+func ExampleLoad() {
+	// Assuming the following Parameter Store parameters:
+	//
+	// | Name                         | Value                | Type         | Key ID        |
+	// | ---------------------------- | -------------------- | ------------ | ------------- |
+	// | /example_service/prod/debug  | false                | String       | -             |
+	// | /example_service/prod/port   | 8080                 | String       | -             |
+	// | /example_service/prod/user   | Ian                  | String       | -             |
+	// | /example_service/prod/rate   | 0.5                  | String       | -             |
+	// | /example_service/prod/secret | zOcZkAGB6aEjN7SAoVBT | SecureString | alias/aws/ssm |
+
+	type Config struct {
+		Debug  bool    `ssm:"debug" default:"true"`
+		Port   int     `ssm:"port"`
+		User   string  `ssm:"user"`
+		Rate   float32 `ssm:"rate"`
+		Secret string  `ssm:"secret" required:"true"`
+	}
+
+	c, err := ssmconfig.Load[Config](context.Background(), "/example_service/prod/")
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	format := "Debug: %v\nPort: %d\nUser: %s\nRate: %f\nSecret: %s\n"
+	_, err = fmt.Printf(format, c.Debug, c.Port, c.User, c.Rate, c.Secret)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,118 @@
+package ssmconfig
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// flightGroup de-duplicates concurrent fetches of the same set of parameters. While a
+// fetch is in flight, identical fetches wait for it and share its result rather than
+// making their own requests.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	res  fetchResult
+	err  error
+}
+
+// flightKey returns the key identifying a fetch of names with the given decryption
+// setting, independent of the order of names.
+func flightKey(names []string, decrypt bool) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return strconv.FormatBool(decrypt) + "\x00" + strings.Join(sorted, "\x00")
+}
+
+// do calls fetch, unless an identical fetch is already in flight, in which case it waits
+// for that fetch and returns a copy of its result. shared reports whether the result of
+// another fetch was returned.
+//
+// The in-flight fetch uses the context of the caller that started it. If the fetch fails
+// because that context is canceled, waiting callers whose own contexts are not done fetch
+// again. If fetch panics, waiting callers return errFetchPanicked.
+func (g *flightGroup) do(ctx context.Context, names []string, decrypt bool, fetch func() (fetchResult, error)) (res fetchResult, shared bool, err error) {
+	key := flightKey(names, decrypt)
+
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return fetchResult{}, true, ctx.Err()
+		case <-c.done:
+		}
+
+		if canceled(c.err) && ctx.Err() == nil {
+			return g.do(ctx, names, decrypt, fetch)
+		}
+		return c.res.copy(), true, c.err
+	}
+
+	// err is replaced by the result of fetch unless it panics.
+	c := &flightCall{done: make(chan struct{}), err: errFetchPanicked}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.res, c.err = fetch()
+	return c.res.copy(), false, c.err
+}
+
+// errFetchPanicked is returned to callers waiting for a fetch that panicked.
+var errFetchPanicked = errors.New("ssmconfig: in-flight fetch panicked")
+
+// canceled reports whether err was caused by a canceled context or an exceeded deadline.
+// The SDK reports these as errors with the code request.CanceledErrorCode, which may be
+// wrapped, e.g. in a *RetryError or *StaleError.
+func canceled(err error) bool {
+	for err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return true
+		}
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == request.CanceledErrorCode {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// copy returns a copy of r that can be modified without affecting r.
+func (r fetchResult) copy() fetchResult {
+	res := r
+	if r.params != nil {
+		res.params = make(map[string]*ssm.Parameter, len(r.params))
+		for k, v := range r.params {
+			res.params[k] = v
+		}
+	}
+	if r.invalid != nil {
+		res.invalid = make(map[string]struct{}, len(r.invalid))
+		for k, v := range r.invalid {
+			res.invalid[k] = v
+		}
+	}
+	return res
+}
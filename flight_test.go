@@ -0,0 +1,190 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+// blockingSSMClient blocks GetParameters calls until release is closed.
+type blockingSSMClient struct {
+	ssmiface.SSMAPI
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (c *blockingSSMClient) GetParameters(input *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+	c.once.Do(func() { close(c.started) })
+	<-c.release
+	return c.SSMAPI.GetParameters(input)
+}
+
+func (c *blockingSSMClient) GetParametersWithContext(ctx aws.Context, input *ssm.GetParametersInput, opts ...request.Option) (*ssm.GetParametersOutput, error) {
+	c.once.Do(func() { close(c.started) })
+	<-c.release
+	return c.SSMAPI.GetParametersWithContext(ctx, input, opts...)
+}
+
+func TestProvider_Process_concurrent(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+		S2 string `ssm:"/strings/s2" default:"string2"`
+	}
+
+	client := &blockingSSMClient{
+		SSMAPI:  s.Client(),
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	p := &ssmconfig.Provider{SSM: client, Cache: ssmconfig.NewCache(time.Minute)}
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	configs := make([]config, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(c *config) {
+			defer wg.Done()
+			errs <- p.ProcessWithContext(context.Background(), "/base", c)
+		}(&configs[i])
+	}
+
+	<-client.started
+	time.Sleep(10 * time.Millisecond) // let the other calls join the in-flight fetch
+	close(client.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+	}
+	for _, c := range configs {
+		if want := (config{S1: "string1", S2: "string2"}); c != want {
+			t.Errorf("Process() want %+v, have %+v", want, c)
+		}
+	}
+	if calls := s.Calls("GetParameters"); calls != 1 {
+		t.Errorf("Process() want 1 GetParameters call, have %d", calls)
+	}
+}
+
+func TestProvider_Process_concurrentCancel(t *testing.T) {
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+	}
+
+	for name, retry := range map[string]*ssmconfig.RetryPolicy{
+		"without retries": nil,
+		"with retries":    ssmconfig.NewRetryPolicy(3),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := ssmtest.NewServer()
+			defer s.Close()
+			s.Put("/base/strings/s1", "string1")
+
+			client := &blockingSSMClient{
+				SSMAPI:  s.Client(),
+				started: make(chan struct{}),
+				release: make(chan struct{}),
+			}
+			p := &ssmconfig.Provider{SSM: client, Retry: retry}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			leader := make(chan error, 1)
+			go func() {
+				leader <- p.ProcessWithContext(ctx, "/base", &config{})
+			}()
+			<-client.started
+
+			waiter := make(chan error, 1)
+			var c config
+			go func() {
+				waiter <- p.ProcessWithContext(context.Background(), "/base", &c)
+			}()
+			time.Sleep(10 * time.Millisecond) // let the waiter join the in-flight fetch
+
+			// The SDK reports the leader's cancellation as a RequestCanceled error.
+			cancel()
+			close(client.release)
+
+			if err := <-leader; err == nil {
+				t.Errorf("Process() expected error for canceled context")
+			}
+			if err := <-waiter; err != nil {
+				t.Fatalf("Process() unexpected error for live context: %v", err)
+			}
+			if c.S1 != "string1" {
+				t.Errorf("Process() want %q, have %q", "string1", c.S1)
+			}
+		})
+	}
+}
+
+// panickingSSMClient panics in GetParameters calls after release is closed.
+type panickingSSMClient struct {
+	blockingSSMClient
+}
+
+func (c *panickingSSMClient) GetParameters(*ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+	c.once.Do(func() { close(c.started) })
+	<-c.release
+	panic("boom")
+}
+
+func (c *panickingSSMClient) GetParametersWithContext(_ aws.Context, input *ssm.GetParametersInput, _ ...request.Option) (*ssm.GetParametersOutput, error) {
+	return c.GetParameters(input)
+}
+
+func TestProvider_Process_concurrentPanic(t *testing.T) {
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+	}
+
+	client := &panickingSSMClient{blockingSSMClient{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}}
+	p := &ssmconfig.Provider{SSM: client}
+
+	leader := make(chan interface{}, 1)
+	go func() {
+		defer func() { leader <- recover() }()
+		p.Process("/base", &config{})
+	}()
+	<-client.started
+
+	waiter := make(chan error, 1)
+	go func() {
+		waiter <- p.Process("/base", &config{})
+	}()
+	time.Sleep(10 * time.Millisecond) // let the waiter join the in-flight fetch
+	close(client.release)
+
+	if r := <-leader; r != "boom" {
+		t.Errorf("Process() want panic %q, have %v", "boom", r)
+	}
+	select {
+	case err := <-waiter:
+		if err == nil {
+			t.Errorf("Process() expected error when the in-flight fetch panicked")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Process() blocked after the in-flight fetch panicked")
+	}
+}
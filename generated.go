@@ -0,0 +1,193 @@
+package ssmconfig
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Generated is implemented by config structs with code generated by
+// cmd/ssmconfig-gen. Provider.Process populates a Generated value using the generated
+// code rather than reflection. Fetching, caching, defaults, and required checks are
+// shared with the reflective path, so the behavior is identical.
+type Generated interface {
+	// SSMFields returns the struct fields with an `ssm` tag, in field order. The result
+	// is cached for each type, so it must not vary between values.
+	SSMFields() []GeneratedField
+
+	// SSMSet decodes s into the i-th field returned by SSMFields.
+	SSMSet(i int, s string) error
+}
+
+// GeneratedField describes a struct field in generated code.
+type GeneratedField struct {
+	Name string // name of the struct field
+	Tag  string // complete struct tag of the field
+}
+
+// generatedSpecs caches the *typeSpec of each Generated type.
+var generatedSpecs sync.Map // map[reflect.Type]*typeSpec
+
+func compileGenerated(g Generated) *typeSpec {
+	key := reflect.TypeOf(g)
+	if ts, ok := generatedSpecs.Load(key); ok {
+		return ts.(*typeSpec)
+	}
+
+	t := key
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fields := g.SSMFields()
+	ts := &typeSpec{fields: make([]fieldPlan, len(fields))}
+	for i, f := range fields {
+		ts.fields[i] = newFieldPlan(i, f.Name, reflect.StructTag(f.Tag))
+		if t.Kind() == reflect.Struct {
+			if sf, ok := t.FieldByName(f.Name); ok && sf.Type.Implements(secretValueType) {
+				ts.fields[i].sensitive = true
+			}
+		}
+	}
+
+	actual, _ := generatedSpecs.LoadOrStore(key, ts)
+	return actual.(*typeSpec)
+}
+
+func buildGeneratedSpec(configPath string, g Generated) structSpec {
+	ts := compileGenerated(g)
+
+	spec := make(structSpec, len(ts.fields))
+	for i := range ts.fields {
+		spec[i] = fieldSpec{
+			fieldPlan: &ts.fields[i],
+			name:      path.Join(configPath, ts.fields[i].tag),
+		}
+	}
+	return spec
+}
+
+// generatedTarget populates a struct using generated code.
+type generatedTarget struct {
+	g Generated
+}
+
+func (t generatedTarget) set(field *fieldPlan, s string) error {
+	return t.g.SSMSet(field.index, s)
+}
+
+// DecodeInt decodes s into an integer. typeName is the type of the field being decoded
+// and is used in errors. It is intended for use by generated code.
+func DecodeInt(s, typeName string) (int64, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("could not decode %q into type %v", s, typeName)
+	}
+	return int64(i), nil
+}
+
+// DecodeFloat decodes s into a float with the given bit size. typeName is the type of the
+// field being decoded and is used in errors. It is intended for use by generated code.
+func DecodeFloat(s string, bitSize int, typeName string) (float64, error) {
+	f, err := strconv.ParseFloat(s, bitSize)
+	if err != nil {
+		return 0, errors.Errorf("could not decode %q into type %v: %v", s, typeName, err)
+	}
+	return f, nil
+}
+
+// DecodeBool decodes s into a bool. typeName is the type of the field being decoded and
+// is used in errors. It is intended for use by generated code.
+func DecodeBool(s, typeName string) (bool, error) {
+	if s != "true" && s != "false" {
+		return false, errors.Errorf("could not decode %q into type %v", s, typeName)
+	}
+	return s == "true", nil
+}
+
+// DecodeUnsupported returns the error for decoding s into an unsupported type. It is
+// intended for use by generated code.
+func DecodeUnsupported(s, typeName string) error {
+	return errors.Errorf("could not decode %q into type %v", s, typeName)
+}
+
+// DecodeField decodes s into the value ptr points to using reflection. It is used by
+// generated code for field types that cannot be decoded statically.
+func DecodeField(ptr interface{}, s string) error {
+	return setValue(reflect.ValueOf(ptr).Elem(), s)
+}
+
+// verifyInputs are decoded by VerifyGenerated in addition to each field's default.
+var verifyInputs = []string{
+	"", "0", "1", "-1", "42", "300", "70000", "3000000000", "42.42", "-0.5", "1e40", "NaN",
+	"0x10", "true", "false", "TRUE", "string", " 1",
+}
+
+// VerifyGenerated reports whether the generated code of g agrees with the reflective
+// path. It compares the generated field table with the struct tags of g's type, and
+// decodes a set of sample values into every field using both paths. g must be a pointer
+// to a struct.
+//
+// It is intended to be called from tests emitted by cmd/ssmconfig-gen -test.
+func VerifyGenerated(g Generated) error {
+	t := reflect.TypeOf(g)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("ssmconfig: %v must be a pointer to a struct", t)
+	}
+	t = t.Elem()
+
+	var want []GeneratedField
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Tag.Get("ssm") != "" {
+			want = append(want, GeneratedField{Name: f.Name, Tag: string(f.Tag)})
+		}
+	}
+	if have := g.SSMFields(); !reflect.DeepEqual(have, want) && !(len(have) == 0 && len(want) == 0) {
+		return errors.Errorf("ssmconfig: generated fields of %v are out of date: have %v, want %v", t, have, want)
+	}
+
+	ts := compileType(t)
+	for i, field := range ts.fields {
+		inputs := append([]string{field.defaultValue}, verifyInputs...)
+		for _, s := range inputs {
+			generated := reflect.New(t)
+			generatedErr := generated.Interface().(Generated).SSMSet(i, s)
+
+			reflective := reflect.New(t)
+			reflectiveErr := field.decode(reflective.Elem().Field(field.index), s)
+
+			if errString(generatedErr) != errString(reflectiveErr) {
+				return errors.Errorf(
+					"ssmconfig: %v.%s: decoding %q: generated error %q, reflective error %q",
+					t, field.field, s, errString(generatedErr), errString(reflectiveErr),
+				)
+			}
+			if !sameValue(generated.Elem(), reflective.Elem()) {
+				return errors.Errorf(
+					"ssmconfig: %v.%s: decoding %q: generated value %v, reflective value %v",
+					t, field.field, s,
+					fmt.Sprint(generated.Elem().Field(field.index)),
+					fmt.Sprint(reflective.Elem().Field(field.index)),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// sameValue reports whether a and b are deeply equal, treating NaNs as equal.
+func sameValue(a, b reflect.Value) bool {
+	return reflect.DeepEqual(a.Interface(), b.Interface()) ||
+		fmt.Sprintf("%#v", a.Interface()) == fmt.Sprintf("%#v", b.Interface())
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
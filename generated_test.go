@@ -0,0 +1,162 @@
+package ssmconfig_test
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+// generatedConfig implements ssmconfig.Generated the way cmd/ssmconfig-gen does.
+type generatedConfig struct {
+	S1 string `ssm:"/strings/s1"`
+	I1 int    `ssm:"/int/i1" default:"42"`
+}
+
+var generatedConfigFields = []ssmconfig.GeneratedField{
+	{Name: "S1", Tag: `ssm:"/strings/s1"`},
+	{Name: "I1", Tag: `ssm:"/int/i1" default:"42"`},
+}
+
+func (c *generatedConfig) SSMFields() []ssmconfig.GeneratedField { return generatedConfigFields }
+
+func (c *generatedConfig) SSMSet(i int, s string) error {
+	switch i {
+	case 0:
+		c.S1 = s
+	case 1:
+		v, err := ssmconfig.DecodeInt(s, "int")
+		if err != nil {
+			return err
+		}
+		c.I1 = int(v)
+	}
+	return nil
+}
+
+// staleFieldsConfig has a field table that does not match its struct tags.
+type staleFieldsConfig struct {
+	S1 string `ssm:"/strings/s1"`
+	I1 int    `ssm:"/int/i2"`
+}
+
+func (c *staleFieldsConfig) SSMFields() []ssmconfig.GeneratedField { return generatedConfigFields[:1] }
+
+func (c *staleFieldsConfig) SSMSet(i int, s string) error {
+	c.S1 = s
+	return nil
+}
+
+// staleDecodeConfig decodes its int field differently than the reflective path.
+type staleDecodeConfig struct {
+	I1 int `ssm:"/int/i1" default:"42"`
+}
+
+var staleDecodeConfigFields = []ssmconfig.GeneratedField{
+	{Name: "I1", Tag: `ssm:"/int/i1" default:"42"`},
+}
+
+func (c *staleDecodeConfig) SSMFields() []ssmconfig.GeneratedField { return staleDecodeConfigFields }
+
+func (c *staleDecodeConfig) SSMSet(i int, s string) error {
+	// ParseInt accepts values that the reflective path rejects, e.g. "0x10".
+	v, err := strconv.ParseInt(s, 0, 64)
+	c.I1 = int(v)
+	return err
+}
+
+// valueGeneratedConfig implements ssmconfig.Generated with value receivers.
+type valueGeneratedConfig struct {
+	S1 string `ssm:"/strings/s1"`
+}
+
+func (c valueGeneratedConfig) SSMFields() []ssmconfig.GeneratedField {
+	return generatedConfigFields[:1]
+}
+
+func (c valueGeneratedConfig) SSMSet(i int, s string) error { return nil }
+
+func TestVerifyGenerated(t *testing.T) {
+	if err := ssmconfig.VerifyGenerated(&generatedConfig{}); err != nil {
+		t.Errorf("VerifyGenerated() unexpected error: %v", err)
+	}
+	if err := ssmconfig.VerifyGenerated(&staleFieldsConfig{}); err == nil {
+		t.Errorf("VerifyGenerated() expected error for out of date fields")
+	}
+	if err := ssmconfig.VerifyGenerated(&staleDecodeConfig{}); err == nil {
+		t.Errorf("VerifyGenerated() expected error for mismatched decoder")
+	}
+}
+
+func TestProvider_Process_generated(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+
+	p := ssmconfig.Provider{SSM: s.Client()}
+
+	var c generatedConfig
+	if err := p.Process("/base", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	if want := (generatedConfig{S1: "string1", I1: 42}); c != want {
+		t.Errorf("Process() want %+v, have %+v", want, c)
+	}
+
+	s.Put("/base/int/i1", "notAnInt")
+	if err := p.Process("/base", &c); err == nil {
+		t.Errorf("Process() expected decode error")
+	}
+}
+
+func TestProvider_Process_generatedNonPointer(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	p := ssmconfig.Provider{SSM: s.Client()}
+
+	err := p.Process("/base", valueGeneratedConfig{})
+	if err == nil || !strings.Contains(err.Error(), "c must be a pointer to a struct") {
+		t.Errorf("Process() want pointer error, have %v", err)
+	}
+}
+
+// freshFieldsConfig returns a new field table from every call to SSMFields.
+type freshFieldsConfig struct {
+	S1 string `ssm:"/strings/s1"`
+}
+
+var freshFieldsCalls int32
+
+func (c *freshFieldsConfig) SSMFields() []ssmconfig.GeneratedField {
+	atomic.AddInt32(&freshFieldsCalls, 1)
+	return []ssmconfig.GeneratedField{{Name: "S1", Tag: `ssm:"/strings/s1"`}}
+}
+
+func (c *freshFieldsConfig) SSMSet(i int, s string) error {
+	c.S1 = s
+	return nil
+}
+
+func TestProvider_Process_generatedFreshFields(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	p := ssmconfig.Provider{SSM: s.Client()}
+
+	for i := 0; i < 3; i++ {
+		var c freshFieldsConfig
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c.S1 != "string1" {
+			t.Errorf("Process() want %q, have %q", "string1", c.S1)
+		}
+	}
+	// The spec is compiled once for the type, however many slices SSMFields returns.
+	if n := atomic.LoadInt32(&freshFieldsCalls); n != 1 {
+		t.Errorf("SSMFields() want 1 call, have %d", n)
+	}
+}
@@ -1,10 +1,13 @@
 module github.com/ianlopshire/go-ssm-config
 
-go 1.11
+go 1.21
 
 require (
 	github.com/aws/aws-sdk-go v1.25.44
-	github.com/pkg/errors v0.8.1
+	github.com/pkg/errors v0.9.1
+)
+
+require (
+	github.com/jmespath/go-jmespath v0.0.0-20180206201540-c2b33e8439af // indirect
 	github.com/stretchr/testify v1.4.0 // indirect
-	golang.org/x/net v0.0.0-20191126235420-ef20fe5d7933 // indirect
 )
@@ -0,0 +1,108 @@
+package ssmconfig
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Holder holds the current version of a config struct of type T and reloads it from
+// Parameter Store. It is safe for concurrent use.
+//
+// Load never blocks and always returns a fully populated snapshot. Snapshots are replaced,
+// never modified, so readers never observe a partially reloaded struct.
+type Holder[T any] struct {
+	// OnError is called by Run when a reload fails. The current snapshot is kept.
+	OnError func(error)
+
+	watcher *Watcher
+	value   atomic.Pointer[T]
+
+	mu          sync.Mutex
+	subscribers map[int]func(old, new *T)
+	nextID      int
+}
+
+// NewHolder loads a T from configPath using p and returns a Holder containing it. T must
+// be a struct type. ctx is only used for the initial load.
+//
+// If a *StaleError is returned the Holder contains the last-known-good values and can be
+// used.
+func NewHolder[T any](ctx context.Context, p *Provider, configPath string) (*Holder[T], error) {
+	h := &Holder[T]{subscribers: map[int]func(old, new *T){}}
+
+	var c T
+	w, err := NewWatcher(ctx, p, configPath, &c)
+	if w == nil {
+		return nil, err
+	}
+
+	h.watcher = w
+	h.watcher.OnChange = h.update
+	h.value.Store(w.Current().(*T))
+	return h, err
+}
+
+// Load returns the current snapshot of the config. The returned value must not be
+// modified.
+func (h *Holder[T]) Load() *T {
+	return h.value.Load()
+}
+
+// Reload fetches the parameters once and replaces the current snapshot if any values have
+// changed. If an error is returned the current snapshot is kept.
+func (h *Holder[T]) Reload(ctx context.Context) error {
+	return h.watcher.Check(ctx)
+}
+
+// Run reloads the config every interval until ctx is done. Errors are reported to
+// OnError.
+func (h *Holder[T]) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if err := h.Reload(ctx); err != nil && h.OnError != nil {
+				h.OnError(err)
+			}
+		}
+	}
+}
+
+// OnChange registers fn to be called with the previous and new snapshots each time the
+// config changes. The returned function removes the subscription.
+func (h *Holder[T]) OnChange(fn func(old, new *T)) (cancel func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextID
+	h.nextID++
+	h.subscribers[id] = fn
+
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers, id)
+	}
+}
+
+func (h *Holder[T]) update(u Update) {
+	next := u.Config.(*T)
+	old := h.value.Swap(next)
+
+	h.mu.Lock()
+	subscribers := make([]func(old, new *T), 0, len(h.subscribers))
+	for _, fn := range h.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	h.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(old, next)
+	}
+}
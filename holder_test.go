@@ -0,0 +1,92 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestHolder(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.Put("/base/int/i1", "42")
+
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+		I1 int    `ssm:"/int/i1"`
+	}
+
+	h, err := ssmconfig.NewHolder[config](context.Background(), &ssmconfig.Provider{SSM: s.Client()}, "/base")
+	if err != nil {
+		t.Fatalf("NewHolder() unexpected error: %v", err)
+	}
+
+	first := h.Load()
+	if want := (config{S1: "string1", I1: 42}); *first != want {
+		t.Fatalf("Load() want %+v, have %+v", want, *first)
+	}
+
+	var calls int
+	cancel := h.OnChange(func(old, new *config) {
+		calls++
+		if old != first {
+			t.Errorf("OnChange() old is not the previous snapshot")
+		}
+		if want := (config{S1: "changed", I1: 42}); *new != want {
+			t.Errorf("OnChange() new want %+v, have %+v", want, *new)
+		}
+	})
+
+	// Readers only ever observe complete snapshots.
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if c := h.Load(); c.I1 != 42 || (c.S1 != "string1" && c.S1 != "changed") {
+				t.Errorf("Load() observed inconsistent snapshot %+v", *c)
+				return
+			}
+		}
+	}()
+
+	s.Put("/base/strings/s1", "changed")
+	if err := h.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	close(done)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("OnChange() want 1 call, have %d", calls)
+	}
+	if first.S1 != "string1" {
+		t.Errorf("Reload() modified the previous snapshot")
+	}
+	if h.Load().S1 != "changed" {
+		t.Errorf("Load() want %q, have %q", "changed", h.Load().S1)
+	}
+
+	cancel()
+	s.Put("/base/strings/s1", "changed again")
+	if err := h.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("OnChange() called after cancel")
+	}
+
+	if _, err := ssmconfig.NewHolder[string](context.Background(), &ssmconfig.Provider{SSM: s.Client()}, "/base"); err == nil {
+		t.Errorf("NewHolder() expected error for non-struct type")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
 )
 
 var IsIntegrationRun bool
@@ -19,12 +20,34 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-func TestProcess_integration(t *testing.T) {
-	if !IsIntegrationRun {
-		t.Skip("Use the -integration flag to run integration tests")
-		return
+// newIntegrationProcess returns the function used to process config in integration tests
+// and a function that releases its resources. When the -integration flag is set Parameter
+// Store is used, otherwise the parameters defined in infra/main.tf are served by a local
+// ssmtest.Server.
+func newIntegrationProcess() (process func(configPath string, c interface{}) error, cleanup func()) {
+	if IsIntegrationRun {
+		return ssmconfig.Process, func() {}
 	}
 
+	s := ssmtest.NewServer()
+
+	s.Put("/go-ssm-config/strings/s1", "string1")
+	s.Put("/go-ssm-config/base/strings/s2", "string2")
+	s.Put("/go-ssm-config/int/i1", "42")
+	s.Put("/go-ssm-config/int/i_zero", "0")
+	s.Put("/go-ssm-config/bool/b1", "true")
+	s.Put("/go-ssm-config/bool/b2", "false")
+	s.Put("/go-ssm-config/float32/f321", "42.42")
+	s.Put("/go-ssm-config/float64/f641", "42.42")
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	return p.Process, s.Close
+}
+
+func TestProcess_integration(t *testing.T) {
+	process, cleanup := newIntegrationProcess()
+	defer cleanup()
+
 	t.Run("base case", func(t *testing.T) {
 		var s struct {
 			S1   string  `ssm:"/strings/s1"`
@@ -35,7 +58,7 @@ func TestProcess_integration(t *testing.T) {
 			F641 float64 `ssm:"/float64/f641"`
 		}
 
-		err := ssmconfig.Process("/go-ssm-config", &s)
+		err := process("/go-ssm-config", &s)
 		if err != nil {
 			t.Errorf("Process() unexpected error: %q", err.Error())
 		}
@@ -71,7 +94,7 @@ func TestProcess_integration(t *testing.T) {
 			S3 string `ssm:"/strings/s3" default:"default"`
 		}
 
-		err := ssmconfig.Process("/go-ssm-config", &s)
+		err := process("/go-ssm-config", &s)
 		if err != nil {
 			t.Errorf("Process() unexpected error: %q", err.Error())
 		}
@@ -88,7 +111,7 @@ func TestProcess_integration(t *testing.T) {
 			BFalse bool   `ssm:"/bool/b2" default:"true"`
 		}
 
-		err := ssmconfig.Process("/go-ssm-config", &s)
+		err := process("/go-ssm-config", &s)
 		if err != nil {
 			t.Errorf("Process() unexpected error: %q", err.Error())
 		}
@@ -111,7 +134,7 @@ func TestProcess_integration(t *testing.T) {
 			S3 string `ssm:"/strings/s3" required:"true"`
 		}
 
-		err := ssmconfig.Process("/go-ssm-config", &s)
+		err := process("/go-ssm-config", &s)
 		if err == nil {
 			t.Error("Process() expexted error but got nil")
 		}
@@ -122,7 +145,7 @@ func TestProcess_integration(t *testing.T) {
 			IZero int `ssm:"/int/i_zero" require:"true"`
 		}
 
-		err := ssmconfig.Process("/go-ssm-config", &s)
+		err := process("/go-ssm-config", &s)
 		if err != nil {
 			t.Errorf("Process() unexpected error: %q", err.Error())
 		}
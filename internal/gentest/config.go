@@ -0,0 +1,30 @@
+// Package gentest contains a config struct with code generated by ssmconfig-gen. It is
+// used to test the generated code against the reflective path.
+package gentest
+
+import (
+	"time"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+)
+
+//go:generate go run ../../cmd/ssmconfig-gen -type Config -test
+
+// Level is a named type declared in the same package as the config.
+type Level int
+
+// Config is a config struct covering the kinds of fields supported by ssmconfig-gen.
+type Config struct {
+	String   string                   `ssm:"/strings/s1" default:"string"`
+	Int      int                      `ssm:"/int/i1" required:"true"`
+	Int64    int64                    `ssm:"/int/i64" default:"64"`
+	Level    Level                    `ssm:"/int/level" default:"3"`
+	Float32  float32                  `ssm:"/float/f32"`
+	Float64  float64                  `ssm:"/float/f64"`
+	Bool     bool                     `ssm:"/bool/b1" default:"true"`
+	Duration time.Duration            `ssm:"/duration/d1"`
+	Bytes    []byte                   `ssm:"/bytes/b1"`
+	Password ssmconfig.Secret[string] `ssm:"/secret/password"`
+	Uint     uint                     `ssm:"/uint/u1"`
+	Ignored  string
+}
@@ -0,0 +1,77 @@
+// Code generated by ssmconfig-gen; DO NOT EDIT.
+
+package gentest
+
+import ssmconfig "github.com/ianlopshire/go-ssm-config"
+
+var _ssmconfigFieldsConfig = []ssmconfig.GeneratedField{
+	{Name: "String", Tag: `ssm:"/strings/s1" default:"string"`},
+	{Name: "Int", Tag: `ssm:"/int/i1" required:"true"`},
+	{Name: "Int64", Tag: `ssm:"/int/i64" default:"64"`},
+	{Name: "Level", Tag: `ssm:"/int/level" default:"3"`},
+	{Name: "Float32", Tag: `ssm:"/float/f32"`},
+	{Name: "Float64", Tag: `ssm:"/float/f64"`},
+	{Name: "Bool", Tag: `ssm:"/bool/b1" default:"true"`},
+	{Name: "Duration", Tag: `ssm:"/duration/d1"`},
+	{Name: "Bytes", Tag: `ssm:"/bytes/b1"`},
+	{Name: "Password", Tag: `ssm:"/secret/password"`},
+	{Name: "Uint", Tag: `ssm:"/uint/u1"`},
+}
+
+// SSMFields implements ssmconfig.Generated.
+func (c *Config) SSMFields() []ssmconfig.GeneratedField {
+	return _ssmconfigFieldsConfig
+}
+
+// SSMSet implements ssmconfig.Generated.
+func (c *Config) SSMSet(i int, s string) error {
+	switch i {
+	case 0:
+		c.String = s
+	case 1:
+		v, err := ssmconfig.DecodeInt(s, "int")
+		if err != nil {
+			return err
+		}
+		c.Int = int(v)
+	case 2:
+		v, err := ssmconfig.DecodeInt(s, "int64")
+		if err != nil {
+			return err
+		}
+		c.Int64 = v
+	case 3:
+		v, err := ssmconfig.DecodeInt(s, "gentest.Level")
+		if err != nil {
+			return err
+		}
+		c.Level = Level(v)
+	case 4:
+		v, err := ssmconfig.DecodeFloat(s, 32, "float32")
+		if err != nil {
+			return err
+		}
+		c.Float32 = float32(v)
+	case 5:
+		v, err := ssmconfig.DecodeFloat(s, 64, "float64")
+		if err != nil {
+			return err
+		}
+		c.Float64 = v
+	case 6:
+		v, err := ssmconfig.DecodeBool(s, "bool")
+		if err != nil {
+			return err
+		}
+		c.Bool = v
+	case 7:
+		return ssmconfig.DecodeField(&c.Duration, s)
+	case 8:
+		return ssmconfig.DecodeField(&c.Bytes, s)
+	case 9:
+		return ssmconfig.DecodeField(&c.Password, s)
+	case 10:
+		return ssmconfig.DecodeUnsupported(s, "uint")
+	}
+	return nil
+}
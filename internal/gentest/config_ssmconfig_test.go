@@ -0,0 +1,19 @@
+// Code generated by ssmconfig-gen; DO NOT EDIT.
+
+package gentest
+
+import (
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+)
+
+func TestSSMConfigGenerated(t *testing.T) {
+	for _, g := range []ssmconfig.Generated{
+		&Config{},
+	} {
+		if err := ssmconfig.VerifyGenerated(g); err != nil {
+			t.Error(err)
+		}
+	}
+}
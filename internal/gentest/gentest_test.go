@@ -0,0 +1,79 @@
+package gentest_test
+
+import (
+	"reflect"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/internal/gentest"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+// reflective has the fields of gentest.Config but not its methods, so it is always
+// processed using reflection.
+type reflective gentest.Config
+
+func TestProcess_generated(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+
+	process := func(t *testing.T) (gentest.Config, error, error) {
+		var generated gentest.Config
+		generatedErr := p.Process("/base", &generated)
+
+		var want reflective
+		reflectiveErr := p.Process("/base", &want)
+
+		if !reflect.DeepEqual(generated, gentest.Config(want)) {
+			t.Errorf("Process() generated %+v, reflective %+v", generated, want)
+		}
+		return generated, generatedErr, reflectiveErr
+	}
+
+	t.Run("required missing", func(t *testing.T) {
+		_, generatedErr, reflectiveErr := process(t)
+		if generatedErr == nil || reflectiveErr == nil || generatedErr.Error() != reflectiveErr.Error() {
+			t.Errorf("Process() generated error %v, reflective error %v", generatedErr, reflectiveErr)
+		}
+	})
+
+	s.Put("/base/int/i1", "-1")
+	s.Put("/base/int/level", "5")
+	s.Put("/base/float/f32", "3.5")
+	s.Put("/base/float/f64", "-6.25")
+	s.Put("/base/bool/b1", "false")
+	s.Put("/base/duration/d1", "10")
+	s.PutSecure("/base/secret/password", "hunter2")
+
+	t.Run("base case", func(t *testing.T) {
+		c, generatedErr, reflectiveErr := process(t)
+		if generatedErr != nil || reflectiveErr != nil {
+			t.Fatalf("Process() generated error %v, reflective error %v", generatedErr, reflectiveErr)
+		}
+
+		want := gentest.Config{
+			String:   "string",
+			Int:      -1,
+			Int64:    64,
+			Level:    5,
+			Float32:  3.5,
+			Float64:  -6.25,
+			Duration: 10,
+			Password: ssmconfig.NewSecret("hunter2"),
+		}
+		if !reflect.DeepEqual(c, want) {
+			t.Errorf("Process() want %+v, have %+v", want, c)
+		}
+	})
+
+	s.Put("/base/uint/u1", "1")
+
+	t.Run("decode error", func(t *testing.T) {
+		_, generatedErr, reflectiveErr := process(t)
+		if generatedErr == nil || reflectiveErr == nil || generatedErr.Error() != reflectiveErr.Error() {
+			t.Errorf("Process() generated error %v, reflective error %v", generatedErr, reflectiveErr)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+// Package schema describes config structs parsed from Go source.
+package schema
+
+import (
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Struct describes a config struct.
+type Struct struct {
+	Package string  `json:"package"`
+	Name    string  `json:"name"`
+	Fields  []Field `json:"fields"`
+}
+
+// Field describes a struct field with an `ssm` tag.
+type Field struct {
+	Name string `json:"name"`
+	Tag  string `json:"tag"`
+
+	// Type is the type of the field as reported by reflect, e.g. "int" or "config.Level".
+	Type string `json:"type"`
+
+	// Kind is the underlying basic type of the field, e.g. "int". It is empty if the
+	// underlying type is not a basic type or could not be determined.
+	Kind string `json:"kind,omitempty"`
+
+	// Local reports whether Type is a basic type or a named type declared in the same
+	// package as the struct.
+	Local bool `json:"-"`
+}
+
+// Exported reports whether the field is exported.
+func (f Field) Exported() bool {
+	return token.IsExported(f.Name)
+}
+
+// Load parses the package in dir and returns the named structs.
+//
+// Imports are resolved on a best-effort basis. The Kind of a field whose type cannot be
+// resolved is empty.
+func Load(dir string, names ...string) ([]*Struct, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not load package in %s", dir)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range pkg.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error:    func(error) {}, // unresolved imports only affect the fields that use them
+	}
+	tpkg, _ := conf.Check(pkg.ImportPath, fset, files, nil)
+
+	structs := make([]*Struct, 0, len(names))
+	for _, name := range names {
+		obj := tpkg.Scope().Lookup(name)
+		if obj == nil {
+			return nil, errors.Errorf("type %s not found in %s", name, dir)
+		}
+		st, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok {
+			return nil, errors.Errorf("%s is not a struct type", name)
+		}
+
+		exprs := fieldExprs(files, name)
+		s := &Struct{Package: tpkg.Name(), Name: name}
+		for i := 0; i < st.NumFields(); i++ {
+			tag := st.Tag(i)
+			if reflect.StructTag(tag).Get("ssm") == "" {
+				continue
+			}
+			f := newField(tpkg, st.Field(i), tag)
+			if basic, ok := st.Field(i).Type().(*types.Basic); ok && basic.Kind() == types.Invalid {
+				// The type could not be resolved; report it as written in the source.
+				f.Type = exprs[f.Name]
+			}
+			s.Fields = append(s.Fields, f)
+		}
+		structs = append(structs, s)
+	}
+	return structs, nil
+}
+
+// modulePath is the import path of ssmconfig. Types from it are reported with the package
+// name ssmconfig, however the package is imported.
+const modulePath = "github.com/ianlopshire/go-ssm-config"
+
+// fieldExprs returns the source of the type of each field of the named struct type, with
+// package qualifiers replaced by package names.
+func fieldExprs(files []*ast.File, name string) map[string]string {
+	exprs := map[string]string{}
+	for _, file := range files {
+		names := map[string]string{} // import name to package name
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			pkg := filepath.Base(path)
+			if path == modulePath {
+				pkg = "ssmconfig"
+			}
+			local := pkg
+			if imp.Name != nil {
+				local = imp.Name.Name
+			}
+			names[local] = pkg
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || spec.Name.Name != name {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return false
+			}
+			for _, field := range st.Fields.List {
+				expr := qualify(field.Type, names)
+				for _, ident := range field.Names {
+					exprs[ident.Name] = expr
+				}
+			}
+			return false
+		})
+	}
+	return exprs
+}
+
+// qualify returns the source of expr with import names replaced by package names.
+func qualify(expr ast.Expr, names map[string]string) string {
+	src := types.ExprString(expr)
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			if pkg, ok := names[x.Name]; ok && pkg != x.Name {
+				src = strings.Replace(src, x.Name+"."+sel.Sel.Name, pkg+"."+sel.Sel.Name, 1)
+			}
+		}
+		return true
+	})
+	return src
+}
+
+func newField(pkg *types.Package, v *types.Var, tag string) Field {
+	f := Field{
+		Name: v.Name(),
+		Tag:  tag,
+		Type: types.TypeString(v.Type(), func(p *types.Package) string { return p.Name() }),
+	}
+
+	basic, ok := v.Type().Underlying().(*types.Basic)
+	if !ok || basic.Kind() == types.Invalid {
+		return f
+	}
+	// Aliases such as byte and rune are reported by their underlying names, as reflect
+	// does.
+	f.Kind = types.Typ[basic.Kind()].Name()
+
+	switch t := v.Type().(type) {
+	case *types.Basic:
+		f.Type = f.Kind
+		f.Local = true
+	case *types.Named:
+		f.Local = t.Obj().Pkg() == pkg
+	}
+	return f
+}
@@ -0,0 +1,60 @@
+// Package seal encrypts short values, such as SecureString parameters, that are written to
+// disk.
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Seal encrypts plaintext with AES-GCM and returns the base64 encoded nonce and
+// ciphertext. key must be 16, 24, or 32 bytes long.
+func Seal(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "could not generate nonce")
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value returned by Seal.
+func Open(key []byte, sealed string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", errors.Wrap(err, "could not decode sealed value")
+	}
+	if len(b) < gcm.NonceSize() {
+		return "", errors.New("sealed value is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.Wrap(err, "could not decrypt sealed value")
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid key")
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,31 @@
+package seal
+
+import "testing"
+
+func TestSealOpen(t *testing.T) {
+	key := []byte("0123456789abcdef")
+
+	sealed, err := Seal(key, "hunter2")
+	if err != nil {
+		t.Fatalf("Seal() unexpected error: %v", err)
+	}
+	if sealed == "hunter2" {
+		t.Fatalf("Seal() returned plaintext")
+	}
+
+	plaintext, err := Open(key, sealed)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if plaintext != "hunter2" {
+		t.Errorf("Open() want %q, have %q", "hunter2", plaintext)
+	}
+
+	if _, err := Open([]byte("fedcba9876543210"), sealed); err == nil {
+		t.Errorf("Open() expected error with wrong key")
+	}
+
+	if _, err := Seal([]byte("short"), "hunter2"); err == nil {
+		t.Errorf("Seal() expected error with invalid key")
+	}
+}
@@ -0,0 +1,72 @@
+package ssmconfig
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/pkg/errors"
+)
+
+// Option configures Load and MustLoad.
+type Option func(*loadOptions)
+
+type loadOptions struct {
+	provider *Provider
+}
+
+// WithProvider configures Load to use p.
+func WithProvider(p *Provider) Option {
+	return func(o *loadOptions) {
+		o.provider = p
+	}
+}
+
+// WithSSM configures Load to use a Provider with the given SSM client.
+func WithSSM(client ssmiface.SSMAPI) Option {
+	return WithProvider(&Provider{SSM: client})
+}
+
+// Load returns a T populated from the parameters under configPath. T must be a struct
+// type. If no Provider is configured, one is created with a new default session.
+//
+// Load uses the same struct tags as Provider.Process. If a *StaleError is returned the
+// value is populated, otherwise the zero value is returned with any error.
+func Load[T any](ctx context.Context, configPath string, opts ...Option) (T, error) {
+	var c T
+	if t := reflect.TypeOf(&c).Elem(); t.Kind() != reflect.Struct {
+		return c, errors.Errorf("ssmconfig: T must be a struct type, have %v", t)
+	}
+
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	p := o.provider
+	if p == nil {
+		sess, err := session.NewSession()
+		if err != nil {
+			return c, errors.Wrap(err, "ssmconfig: could not create aws session")
+		}
+		p = &Provider{SSM: ssm.New(sess)}
+	}
+
+	err := p.ProcessWithContext(ctx, configPath, &c)
+	if _, ok := err.(*StaleError); err != nil && !ok {
+		var zero T
+		return zero, err
+	}
+	return c, err
+}
+
+// MustLoad is like Load but panics if an error occurs.
+func MustLoad[T any](ctx context.Context, configPath string, opts ...Option) T {
+	c, err := Load[T](ctx, configPath, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,71 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestLoad(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.Put("/base/int/i1", "notAnInt")
+
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+		S2 string `ssm:"/strings/s2" default:"string2"`
+	}
+
+	t.Run("base case", func(t *testing.T) {
+		c, err := ssmconfig.Load[config](context.Background(), "/base", ssmconfig.WithSSM(s.Client()))
+		if err != nil {
+			t.Fatalf("Load() unexpected error: %v", err)
+		}
+		if want := (config{S1: "string1", S2: "string2"}); c != want {
+			t.Errorf("Load() want %+v, have %+v", want, c)
+		}
+	})
+
+	t.Run("with provider", func(t *testing.T) {
+		p := &ssmconfig.Provider{SSM: s.Client()}
+		c := ssmconfig.MustLoad[config](context.Background(), "/base", ssmconfig.WithProvider(p))
+		if want := (config{S1: "string1", S2: "string2"}); c != want {
+			t.Errorf("MustLoad() want %+v, have %+v", want, c)
+		}
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		type config struct {
+			S1 string `ssm:"/strings/s1"`
+			I1 int    `ssm:"/int/i1"`
+		}
+		c, err := ssmconfig.Load[config](context.Background(), "/base", ssmconfig.WithSSM(s.Client()))
+		if err == nil {
+			t.Fatalf("Load() expected error")
+		}
+		if c != (config{}) {
+			t.Errorf("Load() want zero value on error, have %+v", c)
+		}
+	})
+
+	t.Run("not a struct", func(t *testing.T) {
+		if _, err := ssmconfig.Load[*config](context.Background(), "/base", ssmconfig.WithSSM(s.Client())); err == nil {
+			t.Errorf("Load() expected error for pointer type")
+		}
+		if _, err := ssmconfig.Load[string](context.Background(), "/base", ssmconfig.WithSSM(s.Client())); err == nil {
+			t.Errorf("Load() expected error for string type")
+		}
+	})
+
+	t.Run("must load panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("MustLoad() expected panic")
+			}
+		}()
+		ssmconfig.MustLoad[string](context.Background(), "/base", ssmconfig.WithSSM(s.Client()))
+	})
+}
@@ -0,0 +1,48 @@
+package ssmconfig
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// redacted replaces SecureString values in logs.
+const redacted = "[REDACTED]"
+
+// logField logs the resolution of field at debug level. value is the value the field was
+// decoded from, and is redacted if it is sensitive.
+func (p *Provider) logField(ctx context.Context, msg string, field *fieldPlan, info FieldInfo, value string, err error) {
+	if p.Logger == nil || !p.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("field", info.Field),
+		slog.String("parameter", info.Parameter),
+		slog.String("source", info.Source.String()),
+	}
+	if info.Source == SourceParameter {
+		attrs = append(attrs,
+			slog.String("type", info.ParameterType),
+			slog.Int64("version", info.Version),
+		)
+	}
+	if info.Source != SourceAbsent {
+		if redactValue(field, info) {
+			value = redacted
+		}
+		attrs = append(attrs, slog.String("value", value))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	p.Logger.LogAttrs(ctx, slog.LevelDebug, msg, attrs...)
+}
+
+// redactValue reports whether the value of field, resolved as described by info, must not
+// be exposed: it came from a SecureString parameter, or the field is sensitive.
+func redactValue(field *fieldPlan, info FieldInfo) bool {
+	return field.sensitive || info.ParameterType == ssm.ParameterTypeSecureString
+}
@@ -0,0 +1,131 @@
+package ssmconfig_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_Process_logger(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.PutSecure("/base/strings/secret", "hunter2")
+
+	type config struct {
+		S1     string `ssm:"/strings/s1"`
+		S2     string `ssm:"/strings/s2" default:"string2"`
+		S3     string `ssm:"/strings/s3"`
+		Secret string `ssm:"/strings/secret"`
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	p := &ssmconfig.Provider{SSM: s.Client(), Logger: logger}
+
+	var c config
+	if err := p.Process("/base", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Fatalf("log exposes SecureString value: %s", buf.String())
+	}
+
+	type entry struct {
+		Msg       string `json:"msg"`
+		Field     string `json:"field"`
+		Parameter string `json:"parameter"`
+		Source    string `json:"source"`
+		Value     string `json:"value"`
+	}
+	var entries []entry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("could not decode log line %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+
+	want := []entry{
+		{Msg: "ssmconfig: resolved field", Field: "S1", Parameter: "/base/strings/s1", Source: "parameter", Value: "string1"},
+		{Msg: "ssmconfig: resolved field", Field: "S2", Parameter: "/base/strings/s2", Source: "default", Value: "string2"},
+		{Msg: "ssmconfig: resolved field", Field: "S3", Parameter: "/base/strings/s3", Source: "absent"},
+		{Msg: "ssmconfig: resolved field", Field: "Secret", Parameter: "/base/strings/secret", Source: "parameter", Value: "[REDACTED]"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("want %d log entries, have %d: %s", len(want), len(entries), buf.String())
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("log entry %d: want %+v, have %+v", i, want[i], entries[i])
+		}
+	}
+
+	t.Run("batch error", func(t *testing.T) {
+		buf.Reset()
+		s.Fail("GetParameters", "AccessDeniedException", 1)
+
+		if err := p.Process("/base", &c); err == nil {
+			t.Fatalf("Process() expected error")
+		}
+		if !strings.Contains(buf.String(), "GetParameters request failed") || !strings.Contains(buf.String(), "AccessDeniedException") {
+			t.Errorf("log missing batch error: %s", buf.String())
+		}
+	})
+
+	t.Run("info level", func(t *testing.T) {
+		buf.Reset()
+		p := &ssmconfig.Provider{SSM: s.Client(), Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("unexpected log output at info level: %s", buf.String())
+		}
+	})
+
+	t.Run("sensitive fields", func(t *testing.T) {
+		s.Put("/base/strings/plain-secret", "hunter3")
+		s.Put("/base/strings/not-an-int", "hunter4")
+
+		type sensitiveConfig struct {
+			Secret    ssmconfig.Secret[string] `ssm:"/strings/plain-secret"`
+			Tagged    string                   `ssm:"/strings/plain-secret" sensitive:"true"`
+			Defaulted string                   `ssm:"/strings/missing" default:"hunter5" sensitive:"true"`
+		}
+
+		buf.Reset()
+		var sc sensitiveConfig
+		if err := p.Process("/base", &sc); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		for _, value := range []string{"hunter3", "hunter5"} {
+			if strings.Contains(buf.String(), value) {
+				t.Errorf("log exposes sensitive value %q: %s", value, buf.String())
+			}
+		}
+		if n := strings.Count(buf.String(), `"value":"[REDACTED]"`); n != 3 {
+			t.Errorf("want 3 redacted values, have %d: %s", n, buf.String())
+		}
+
+		type decodeConfig struct {
+			Tagged int `ssm:"/strings/not-an-int" sensitive:"true"`
+		}
+
+		buf.Reset()
+		var dc decodeConfig
+		err := p.Process("/base", &dc)
+		if err == nil {
+			t.Fatalf("Process() expected error")
+		}
+		if strings.Contains(err.Error(), "hunter4") || strings.Contains(buf.String(), "hunter4") {
+			t.Errorf("decode error exposes sensitive value: %v\n%s", err, buf.String())
+		}
+	})
+}
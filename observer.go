@@ -0,0 +1,196 @@
+package ssmconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Observer receives callbacks as a Provider processes configs. It can be used to record
+// metrics, traces, and logs. Callbacks are made synchronously, so they should return
+// quickly, and an Observer shared by Providers used concurrently must be safe for
+// concurrent use.
+//
+// Parameter values are never passed to an Observer.
+//
+// Implementations should embed NopObserver so that they continue to compile if methods
+// are added.
+type Observer interface {
+	// ProcessStart is called when a call to Process begins. The returned context is used
+	// for the rest of the call, including the other callbacks.
+	ProcessStart(ctx context.Context, configPath string) context.Context
+
+	// ProcessEnd is called when a call to Process returns.
+	ProcessEnd(ctx context.Context, info ProcessInfo)
+
+	// FetchStart is called before parameters are fetched. The returned context is used for
+	// the fetch, including the requests made to Parameter Store.
+	FetchStart(ctx context.Context, names []string) context.Context
+
+	// FetchEnd is called when a fetch of parameters completes.
+	FetchEnd(ctx context.Context, info FetchInfo)
+
+	// BatchStart is called before each GetParameters request made by a fetch. A fetch
+	// makes one request for every 10 uncached parameters. The returned context is used for
+	// the request and its retries.
+	BatchStart(ctx context.Context, names []string) context.Context
+
+	// BatchEnd is called when a GetParameters request completes, after any retries.
+	BatchEnd(ctx context.Context, info BatchInfo)
+
+	// FieldResolved is called when a struct field has been resolved to a parameter, its
+	// default value, or nothing.
+	FieldResolved(ctx context.Context, info FieldInfo)
+
+	// DecodeError is called when the value for a struct field cannot be decoded.
+	DecodeError(ctx context.Context, info FieldInfo)
+}
+
+// NopObserver is an Observer that does nothing. It is intended to be embedded in
+// implementations of Observer.
+type NopObserver struct{}
+
+// ProcessStart returns ctx.
+func (NopObserver) ProcessStart(ctx context.Context, _ string) context.Context { return ctx }
+
+// ProcessEnd does nothing.
+func (NopObserver) ProcessEnd(context.Context, ProcessInfo) {}
+
+// FetchStart returns ctx.
+func (NopObserver) FetchStart(ctx context.Context, _ []string) context.Context { return ctx }
+
+// FetchEnd does nothing.
+func (NopObserver) FetchEnd(context.Context, FetchInfo) {}
+
+// BatchStart returns ctx.
+func (NopObserver) BatchStart(ctx context.Context, _ []string) context.Context { return ctx }
+
+// BatchEnd does nothing.
+func (NopObserver) BatchEnd(context.Context, BatchInfo) {}
+
+// FieldResolved does nothing.
+func (NopObserver) FieldResolved(context.Context, FieldInfo) {}
+
+// DecodeError does nothing.
+func (NopObserver) DecodeError(context.Context, FieldInfo) {}
+
+// ProcessInfo describes a completed call to Process.
+type ProcessInfo struct {
+	ConfigPath string
+	Duration   time.Duration
+	Err        error
+}
+
+// FetchInfo describes a completed fetch of parameters.
+type FetchInfo struct {
+	// Names is the names of the parameters fetched, including those served from the cache.
+	Names []string
+
+	// Cached is the number of parameters served from the cache.
+	Cached int
+
+	// Invalid is the names Parameter Store returned as invalid, i.e. the parameters that
+	// do not exist.
+	Invalid []string
+
+	// Requests is the number of requests made to Parameter Store, including retries.
+	Requests int
+
+	// Retries is the number of requests that were retries.
+	Retries int
+
+	// Shared reports whether the fetch waited for an identical fetch that was already in
+	// flight. Requests and Retries are those of the shared fetch.
+	Shared bool
+
+	Duration time.Duration
+	Err      error
+}
+
+// BatchInfo describes a completed GetParameters request.
+type BatchInfo struct {
+	// Names is the names of the parameters requested.
+	Names []string
+
+	// Invalid is the number of parameters Parameter Store returned as invalid.
+	Invalid int
+
+	// Retries is the number of times the request was retried.
+	Retries int
+
+	Duration time.Duration
+	Err      error
+}
+
+// Source is where the value of a struct field came from.
+type Source int
+
+const (
+	// SourceAbsent means the field was left unchanged because its parameter does not exist
+	// and it has no default value.
+	SourceAbsent Source = iota
+
+	// SourceParameter means the field was set from its parameter.
+	SourceParameter
+
+	// SourceDefault means the field was set to its default value.
+	SourceDefault
+)
+
+// MarshalText encodes s as its name, e.g. "parameter".
+func (s Source) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a Source from its name.
+func (s *Source) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "parameter":
+		*s = SourceParameter
+	case "default":
+		*s = SourceDefault
+	case "absent":
+		*s = SourceAbsent
+	default:
+		return errors.Errorf("ssmconfig: unknown source %q", text)
+	}
+	return nil
+}
+
+func (s Source) String() string {
+	switch s {
+	case SourceParameter:
+		return "parameter"
+	case SourceDefault:
+		return "default"
+	default:
+		return "absent"
+	}
+}
+
+// FieldInfo describes how a struct field was resolved.
+type FieldInfo struct {
+	// Field is the name of the struct field.
+	Field string
+
+	// Parameter is the resolved name of the field's parameter.
+	Parameter string
+
+	Source Source
+
+	// ParameterType is the type of the parameter, e.g. "SecureString", if Source is
+	// SourceParameter.
+	ParameterType string
+
+	// Version is the version of the parameter, if Source is SourceParameter.
+	Version int64
+}
+
+// observer returns p.Observer, or a NopObserver if it is nil.
+func (p *Provider) observer() Observer {
+	if p.Observer == nil {
+		return NopObserver{}
+	}
+	return p.Observer
+}
@@ -0,0 +1,130 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ssm"
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+type recordingObserver struct {
+	ssmconfig.NopObserver
+
+	mu           sync.Mutex
+	processes    []ssmconfig.ProcessInfo
+	fetches      []ssmconfig.FetchInfo
+	fields       []ssmconfig.FieldInfo
+	decodeErrors []ssmconfig.FieldInfo
+}
+
+func (o *recordingObserver) ProcessEnd(_ context.Context, info ssmconfig.ProcessInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.processes = append(o.processes, info)
+}
+
+func (o *recordingObserver) FetchEnd(_ context.Context, info ssmconfig.FetchInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.fetches = append(o.fetches, info)
+}
+
+func (o *recordingObserver) FieldResolved(_ context.Context, info ssmconfig.FieldInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.fields = append(o.fields, info)
+}
+
+func (o *recordingObserver) DecodeError(_ context.Context, info ssmconfig.FieldInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.decodeErrors = append(o.decodeErrors, info)
+}
+
+func TestProvider_Process_observer(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.PutSecure("/base/strings/secret", "hunter2")
+
+	type config struct {
+		S1     string `ssm:"/strings/s1"`
+		S2     string `ssm:"/strings/s2" default:"string2"`
+		S3     string `ssm:"/strings/s3"`
+		Secret string `ssm:"/strings/secret"`
+	}
+
+	o := &recordingObserver{}
+	p := &ssmconfig.Provider{SSM: s.Client(), Cache: ssmconfig.NewCache(time.Minute), Observer: o}
+
+	var c config
+	if err := p.Process("/base", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	wantFields := []ssmconfig.FieldInfo{
+		{Field: "S1", Parameter: "/base/strings/s1", Source: ssmconfig.SourceParameter, ParameterType: ssm.ParameterTypeString, Version: 1},
+		{Field: "S2", Parameter: "/base/strings/s2", Source: ssmconfig.SourceDefault},
+		{Field: "S3", Parameter: "/base/strings/s3", Source: ssmconfig.SourceAbsent},
+		{Field: "Secret", Parameter: "/base/strings/secret", Source: ssmconfig.SourceParameter, ParameterType: ssm.ParameterTypeSecureString, Version: 1},
+	}
+	if !reflect.DeepEqual(o.fields, wantFields) {
+		t.Errorf("FieldResolved() want %+v, have %+v", wantFields, o.fields)
+	}
+
+	if len(o.fetches) != 1 {
+		t.Fatalf("FetchEnd() want 1 call, have %d", len(o.fetches))
+	}
+	fetch := o.fetches[0]
+	if len(fetch.Names) != 4 || fetch.Requests != 1 || fetch.Cached != 0 || fetch.Err != nil {
+		t.Errorf("FetchEnd() unexpected info: %+v", fetch)
+	}
+	if want := []string{"/base/strings/s2", "/base/strings/s3"}; !reflect.DeepEqual(fetch.Invalid, want) {
+		t.Errorf("FetchEnd() want invalid %v, have %v", want, fetch.Invalid)
+	}
+
+	if len(o.processes) != 1 || o.processes[0].ConfigPath != "/base" || o.processes[0].Err != nil {
+		t.Errorf("ProcessEnd() unexpected info: %+v", o.processes)
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		fetch := o.fetches[len(o.fetches)-1]
+		if fetch.Cached != 4 || fetch.Requests != 0 {
+			t.Errorf("FetchEnd() unexpected info: %+v", fetch)
+		}
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		type config struct {
+			Secret int `ssm:"/strings/secret"`
+		}
+
+		var c config
+		err := p.Process("/base", &c)
+		if err == nil {
+			t.Fatalf("Process() expected error")
+		}
+		if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("Process() error exposes SecureString value: %v", err)
+		}
+
+		want := []ssmconfig.FieldInfo{
+			{Field: "Secret", Parameter: "/base/strings/secret", Source: ssmconfig.SourceParameter, ParameterType: ssm.ParameterTypeSecureString, Version: 1},
+		}
+		if !reflect.DeepEqual(o.decodeErrors, want) {
+			t.Errorf("DecodeError() want %+v, have %+v", want, o.decodeErrors)
+		}
+		if last := o.processes[len(o.processes)-1]; last.Err != err {
+			t.Errorf("ProcessEnd() want error %v, have %v", err, last.Err)
+		}
+	})
+}
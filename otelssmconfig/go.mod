@@ -0,0 +1,23 @@
+module github.com/ianlopshire/go-ssm-config/otelssmconfig
+
+go 1.21
+
+replace github.com/ianlopshire/go-ssm-config => ../
+
+require (
+	github.com/ianlopshire/go-ssm-config v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.16.0
+	go.opentelemetry.io/otel/metric v1.16.0
+	go.opentelemetry.io/otel/sdk v1.16.0
+	go.opentelemetry.io/otel/sdk/metric v0.39.0
+	go.opentelemetry.io/otel/trace v1.16.0
+)
+
+require (
+	github.com/aws/aws-sdk-go v1.25.44 // indirect
+	github.com/go-logr/logr v1.2.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/jmespath/go-jmespath v0.0.0-20180206201540-c2b33e8439af // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/sys v0.8.0 // indirect
+)
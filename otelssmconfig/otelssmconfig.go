@@ -0,0 +1,206 @@
+// Package otelssmconfig records OpenTelemetry traces and metrics for ssmconfig.
+//
+// An Observer is set on a Provider:
+//
+//	o, err := otelssmconfig.NewObserver()
+//	provider := &ssmconfig.Provider{SSM: ssm.New(sess), Observer: o}
+//
+// Each call to Process is recorded as a span, with a child span for each GetParameters
+// request. Parameter values are never recorded.
+package otelssmconfig
+
+import (
+	"context"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer and meter.
+const instrumentationName = "github.com/ianlopshire/go-ssm-config/otelssmconfig"
+
+// Attribute keys recorded on spans and metrics.
+const (
+	PathKey           = attribute.Key("ssmconfig.path")
+	ParameterCountKey = attribute.Key("ssmconfig.parameter.count")
+	InvalidCountKey   = attribute.Key("ssmconfig.parameter.invalid_count")
+	RetriesKey        = attribute.Key("ssmconfig.retries")
+	FieldKey          = attribute.Key("ssmconfig.field")
+	ParameterNameKey  = attribute.Key("ssmconfig.parameter.name")
+	ErrorKindKey      = attribute.Key("ssmconfig.error.kind")
+)
+
+// Error kinds recorded by the errors counter.
+const (
+	ErrorKindProcess = "process"
+	ErrorKindRequest = "request"
+	ErrorKindDecode  = "decode"
+)
+
+// Observer is an ssmconfig.Observer that records OpenTelemetry spans and metrics.
+//
+// The following metrics are recorded:
+//
+//   - ssmconfig.process.duration: histogram of the duration of calls to Process.
+//   - ssmconfig.request.duration: histogram of the duration of GetParameters requests.
+//   - ssmconfig.retries: counter of retried GetParameters requests.
+//   - ssmconfig.errors: counter of errors, by ssmconfig.error.kind.
+type Observer struct {
+	ssmconfig.NopObserver
+
+	tracer trace.Tracer
+
+	processDuration metric.Float64Histogram
+	requestDuration metric.Float64Histogram
+	retries         metric.Int64Counter
+	errors          metric.Int64Counter
+}
+
+var _ ssmconfig.Observer = (*Observer)(nil)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures an Observer.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider used to create spans. The global provider is
+// used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithMeterProvider sets the MeterProvider used to record metrics. The global provider is
+// used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = mp }
+}
+
+// NewObserver returns an Observer configured by opts.
+func NewObserver(opts ...Option) (*Observer, error) {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	o := &Observer{tracer: c.tracerProvider.Tracer(instrumentationName)}
+	meter := c.meterProvider.Meter(instrumentationName)
+
+	var err error
+	if o.processDuration, err = meter.Float64Histogram(
+		"ssmconfig.process.duration",
+		metric.WithDescription("Duration of calls to Process."),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	if o.requestDuration, err = meter.Float64Histogram(
+		"ssmconfig.request.duration",
+		metric.WithDescription("Duration of GetParameters requests, including retries."),
+		metric.WithUnit("s"),
+	); err != nil {
+		return nil, err
+	}
+	if o.retries, err = meter.Int64Counter(
+		"ssmconfig.retries",
+		metric.WithDescription("Number of retried GetParameters requests."),
+	); err != nil {
+		return nil, err
+	}
+	if o.errors, err = meter.Int64Counter(
+		"ssmconfig.errors",
+		metric.WithDescription("Number of errors processing configs."),
+	); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+type pathKey struct{}
+
+// ProcessStart starts a span for the call to Process.
+func (o *Observer) ProcessStart(ctx context.Context, configPath string) context.Context {
+	ctx = context.WithValue(ctx, pathKey{}, configPath)
+	ctx, _ = o.tracer.Start(ctx, "ssmconfig.Process",
+		trace.WithAttributes(PathKey.String(configPath)),
+	)
+	return ctx
+}
+
+// ProcessEnd ends the span for the call to Process and records its duration.
+func (o *Observer) ProcessEnd(ctx context.Context, info ssmconfig.ProcessInfo) {
+	span := trace.SpanFromContext(ctx)
+	attrs := []attribute.KeyValue{PathKey.String(info.ConfigPath)}
+
+	if info.Err != nil {
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+		o.errors.Add(ctx, 1, metric.WithAttributes(append(attrs, ErrorKindKey.String(ErrorKindProcess))...))
+	}
+	span.End()
+
+	o.processDuration.Record(ctx, info.Duration.Seconds(), metric.WithAttributes(attrs...))
+}
+
+// BatchStart starts a span for a GetParameters request.
+func (o *Observer) BatchStart(ctx context.Context, names []string) context.Context {
+	ctx, _ = o.tracer.Start(ctx, "SSM.GetParameters",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(pathAttrs(ctx),
+			attribute.String("rpc.system", "aws-api"),
+			attribute.String("rpc.service", "SSM"),
+			attribute.String("rpc.method", "GetParameters"),
+			ParameterCountKey.Int(len(names)),
+		)...),
+	)
+	return ctx
+}
+
+// BatchEnd ends the span for a GetParameters request and records its duration.
+func (o *Observer) BatchEnd(ctx context.Context, info ssmconfig.BatchInfo) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		InvalidCountKey.Int(info.Invalid),
+		RetriesKey.Int(info.Retries),
+	)
+
+	attrs := pathAttrs(ctx)
+	if info.Err != nil {
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+		o.errors.Add(ctx, 1, metric.WithAttributes(append(attrs, ErrorKindKey.String(ErrorKindRequest))...))
+	}
+	span.End()
+
+	o.requestDuration.Record(ctx, info.Duration.Seconds(), metric.WithAttributes(attrs...))
+	if info.Retries > 0 {
+		o.retries.Add(ctx, int64(info.Retries), metric.WithAttributes(attrs...))
+	}
+}
+
+// DecodeError adds an event to the span for the call to Process and counts the error.
+func (o *Observer) DecodeError(ctx context.Context, info ssmconfig.FieldInfo) {
+	trace.SpanFromContext(ctx).AddEvent("decode error", trace.WithAttributes(
+		FieldKey.String(info.Field),
+		ParameterNameKey.String(info.Parameter),
+	))
+	o.errors.Add(ctx, 1, metric.WithAttributes(append(pathAttrs(ctx), ErrorKindKey.String(ErrorKindDecode))...))
+}
+
+// pathAttrs returns the attributes identifying the config path of the call to Process
+// that ctx belongs to, if any.
+func pathAttrs(ctx context.Context) []attribute.KeyValue {
+	if path, ok := ctx.Value(pathKey{}).(string); ok {
+		return []attribute.KeyValue{PathKey.String(path)}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package otelssmconfig_test
+
+import (
+	"context"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/otelssmconfig"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestObserver(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.Put("/base/int/i1", "notAnInt")
+	s.Fail("GetParameters", "ThrottlingException", 1)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	o, err := otelssmconfig.NewObserver(
+		otelssmconfig.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otelssmconfig.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatalf("NewObserver() unexpected error: %v", err)
+	}
+
+	retry := ssmconfig.NewRetryPolicy(2)
+	retry.BaseDelay = 0
+	p := &ssmconfig.Provider{SSM: s.Client(), Retry: retry, Observer: o}
+
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+		S2 string `ssm:"/strings/s2"`
+		I1 int    `ssm:"/int/i1"`
+	}
+	var c config
+	if err := p.Process("/base", &c); err == nil {
+		t.Fatalf("Process() expected decode error")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("want 2 spans, have %d", len(ended))
+	}
+	batch, process := ended[0], ended[1]
+
+	if process.Name() != "ssmconfig.Process" || process.Status().Code != codes.Error {
+		t.Errorf("unexpected process span: %s %v", process.Name(), process.Status())
+	}
+	if len(process.Events()) == 0 || process.Events()[0].Name != "decode error" {
+		t.Errorf("process span missing decode error event: %v", process.Events())
+	}
+
+	if batch.Name() != "SSM.GetParameters" || batch.Parent().SpanID() != process.SpanContext().SpanID() {
+		t.Errorf("unexpected batch span: %s, parent %v", batch.Name(), batch.Parent().SpanID())
+	}
+	want := map[attribute.Key]attribute.Value{
+		otelssmconfig.PathKey:           attribute.StringValue("/base"),
+		otelssmconfig.ParameterCountKey: attribute.IntValue(3),
+		otelssmconfig.InvalidCountKey:   attribute.IntValue(1),
+		otelssmconfig.RetriesKey:        attribute.IntValue(1),
+	}
+	have := map[attribute.Key]attribute.Value{}
+	for _, kv := range batch.Attributes() {
+		have[kv.Key] = kv.Value
+	}
+	for k, v := range want {
+		if have[k] != v {
+			t.Errorf("batch span attribute %s: want %v, have %v", k, v.Emit(), have[k].Emit())
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() unexpected error: %v", err)
+	}
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	for _, name := range []string{"ssmconfig.process.duration", "ssmconfig.request.duration"} {
+		if h, ok := metrics[name].(metricdata.Histogram[float64]); !ok || len(h.DataPoints) != 1 || h.DataPoints[0].Count != 1 {
+			t.Errorf("%s: want 1 observation, have %+v", name, metrics[name])
+		}
+	}
+	if sum, ok := metrics["ssmconfig.retries"].(metricdata.Sum[int64]); !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+		t.Errorf("ssmconfig.retries: want 1, have %+v", metrics["ssmconfig.retries"])
+	}
+
+	errorsByKind := map[string]int64{}
+	if sum, ok := metrics["ssmconfig.errors"].(metricdata.Sum[int64]); ok {
+		for _, dp := range sum.DataPoints {
+			kind, _ := dp.Attributes.Value(otelssmconfig.ErrorKindKey)
+			errorsByKind[kind.AsString()] += dp.Value
+		}
+	}
+	if errorsByKind[otelssmconfig.ErrorKindDecode] != 1 || errorsByKind[otelssmconfig.ErrorKindProcess] != 1 {
+		t.Errorf("ssmconfig.errors: unexpected counts %v", errorsByKind)
+	}
+}
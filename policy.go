@@ -0,0 +1,152 @@
+package ssmconfig
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PolicyOptions parameterizes the IAM policy returned by Provider.Policy.
+type PolicyOptions struct {
+	// Partition is the AWS partition of the ARNs. If it is empty "aws" is used.
+	Partition string
+
+	// Region and Account are the region and account ID of the parameters. If either is
+	// empty "*" is used in its place.
+	Region  string
+	Account string
+
+	// KeyARNs are the KMS keys SecureString parameters are encrypted with. If it is empty
+	// kms:Decrypt is granted on every key in the region and account, but only when used by
+	// Parameter Store.
+	KeyARNs []string
+
+	// Watch grants ssm:DescribeParameters, which is required by Watcher.
+	Watch bool
+}
+
+func (o PolicyOptions) partition() string {
+	if o.Partition == "" {
+		return "aws"
+	}
+	return o.Partition
+}
+
+func (o PolicyOptions) region() string {
+	if o.Region == "" {
+		return "*"
+	}
+	return o.Region
+}
+
+func (o PolicyOptions) account() string {
+	if o.Account == "" {
+		return "*"
+	}
+	return o.Account
+}
+
+// parameterARN returns the ARN of the named parameter.
+func (o PolicyOptions) parameterARN(name string) string {
+	return "arn:" + o.partition() + ":ssm:" + o.region() + ":" + o.account() + ":parameter/" + strings.TrimPrefix(name, "/")
+}
+
+// IAMPolicy is an IAM policy document. It encodes to the JSON expected by IAM.
+type IAMPolicy struct {
+	Version   string
+	Statement []IAMStatement
+}
+
+// IAMStatement is a statement of an IAMPolicy.
+type IAMStatement struct {
+	Sid       string `json:",omitempty"`
+	Effect    string
+	Action    []string
+	Resource  []string
+	Condition map[string]map[string]string `json:",omitempty"`
+}
+
+// Policy returns the least-privilege IAM policy needed for p to process c with each of
+// configPaths. c must be a pointer to a struct; only its type is used.
+//
+// ssm:GetParameters is granted on exactly the parameters of the fields of c. kms:Decrypt
+// is granted if any field that is decrypted by p is tagged `secure:"true"` or
+// `sensitive:"true"`, or is a Secret. ssm:DescribeParameters, which cannot be restricted to
+// specific parameters, is granted if p.Secure has AllowedKeyIDs or opts.Watch is set.
+func (p *Provider) Policy(c interface{}, configPaths []string, opts PolicyOptions) (*IAMPolicy, error) {
+	if len(configPaths) == 0 {
+		return nil, errors.New("ssmconfig: at least one config path is required")
+	}
+
+	seen := map[string]struct{}{}
+	var resources []string
+	decrypt := false
+	for _, configPath := range configPaths {
+		spec, _, err := specFor(configPath, c)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range spec {
+			field := spec[i]
+			if (field.secure || field.sensitive) && p.decrypts(field.fieldPlan) {
+				decrypt = true
+			}
+
+			arn := opts.parameterARN(field.name)
+			if _, ok := seen[arn]; ok {
+				continue
+			}
+			seen[arn] = struct{}{}
+			resources = append(resources, arn)
+		}
+	}
+	if len(resources) == 0 {
+		return nil, errors.New("ssmconfig: c has no fields with an `ssm` tag")
+	}
+	sort.Strings(resources)
+
+	policy := &IAMPolicy{
+		Version: "2012-10-17",
+		Statement: []IAMStatement{{
+			Sid:      "ReadParameters",
+			Effect:   "Allow",
+			Action:   []string{"ssm:GetParameters"},
+			Resource: resources,
+		}},
+	}
+
+	if opts.Watch || (p.Secure != nil && len(p.Secure.AllowedKeyIDs) > 0) {
+		policy.Statement = append(policy.Statement, IAMStatement{
+			Sid:      "DescribeParameters",
+			Effect:   "Allow",
+			Action:   []string{"ssm:DescribeParameters"},
+			Resource: []string{"*"},
+		})
+	}
+
+	if decrypt {
+		stmt := IAMStatement{
+			Sid:      "DecryptParameters",
+			Effect:   "Allow",
+			Action:   []string{"kms:Decrypt"},
+			Resource: opts.KeyARNs,
+		}
+		if len(opts.KeyARNs) == 0 {
+			stmt.Resource = []string{"arn:" + opts.partition() + ":kms:" + opts.region() + ":" + opts.account() + ":key/*"}
+			if opts.Region == "" {
+				stmt.Condition = map[string]map[string]string{
+					"StringLike": {"kms:ViaService": "ssm.*.amazonaws.com"},
+				}
+			} else {
+				stmt.Condition = map[string]map[string]string{
+					"StringEquals": {"kms:ViaService": "ssm." + opts.Region + ".amazonaws.com"},
+				}
+			}
+		}
+		policy.Statement = append(policy.Statement, stmt)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,120 @@
+package ssmconfig_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+)
+
+func TestProvider_Policy(t *testing.T) {
+	type config struct {
+		Region   string                   `ssm:"region"`
+		Timeout  int                      `ssm:"timeout" default:"5"`
+		Password ssmconfig.Secret[string] `ssm:"db/password"`
+		Ignored  string
+	}
+	opts := ssmconfig.PolicyOptions{Region: "us-east-1", Account: "123456789012"}
+
+	t.Run("parameters and decryption", func(t *testing.T) {
+		p := &ssmconfig.Provider{}
+		policy, err := p.Policy(&config{}, []string{"/prod/app", "/staging/app"}, opts)
+		if err != nil {
+			t.Fatalf("Policy() unexpected error: %v", err)
+		}
+
+		want := &ssmconfig.IAMPolicy{
+			Version: "2012-10-17",
+			Statement: []ssmconfig.IAMStatement{
+				{
+					Sid:    "ReadParameters",
+					Effect: "Allow",
+					Action: []string{"ssm:GetParameters"},
+					Resource: []string{
+						"arn:aws:ssm:us-east-1:123456789012:parameter/prod/app/db/password",
+						"arn:aws:ssm:us-east-1:123456789012:parameter/prod/app/region",
+						"arn:aws:ssm:us-east-1:123456789012:parameter/prod/app/timeout",
+						"arn:aws:ssm:us-east-1:123456789012:parameter/staging/app/db/password",
+						"arn:aws:ssm:us-east-1:123456789012:parameter/staging/app/region",
+						"arn:aws:ssm:us-east-1:123456789012:parameter/staging/app/timeout",
+					},
+				},
+				{
+					Sid:       "DecryptParameters",
+					Effect:    "Allow",
+					Action:    []string{"kms:Decrypt"},
+					Resource:  []string{"arn:aws:kms:us-east-1:123456789012:key/*"},
+					Condition: map[string]map[string]string{"StringEquals": {"kms:ViaService": "ssm.us-east-1.amazonaws.com"}},
+				},
+			},
+		}
+		if !reflect.DeepEqual(policy, want) {
+			t.Errorf("Policy() unexpected policy:\nwant %+v\nhave %+v", want, policy)
+		}
+	})
+
+	t.Run("without decryption", func(t *testing.T) {
+		p := &ssmconfig.Provider{DisableDecryption: true}
+		policy, err := p.Policy(&config{}, []string{"/prod/app"}, opts)
+		if err != nil {
+			t.Fatalf("Policy() unexpected error: %v", err)
+		}
+		if len(policy.Statement) != 1 || policy.Statement[0].Sid != "ReadParameters" {
+			t.Errorf("Policy() want only ReadParameters, have %+v", policy.Statement)
+		}
+	})
+
+	t.Run("key ARNs and describe", func(t *testing.T) {
+		p := &ssmconfig.Provider{Secure: &ssmconfig.SecurePolicy{AllowedKeyIDs: []string{"alias/config"}}}
+		opts := opts
+		opts.KeyARNs = []string{"arn:aws:kms:us-east-1:123456789012:key/abcd"}
+		policy, err := p.Policy(&config{}, []string{"/prod/app"}, opts)
+		if err != nil {
+			t.Fatalf("Policy() unexpected error: %v", err)
+		}
+
+		var sids []string
+		for _, stmt := range policy.Statement {
+			sids = append(sids, stmt.Sid)
+		}
+		if want := []string{"ReadParameters", "DescribeParameters", "DecryptParameters"}; !reflect.DeepEqual(sids, want) {
+			t.Fatalf("Policy() want statements %v, have %v", want, sids)
+		}
+		if stmt := policy.Statement[2]; !reflect.DeepEqual(stmt.Resource, opts.KeyARNs) || stmt.Condition != nil {
+			t.Errorf("Policy() unexpected decrypt statement: %+v", stmt)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		type plain struct {
+			Region string `ssm:"region"`
+		}
+		p := &ssmconfig.Provider{}
+		policy, err := p.Policy(&plain{}, []string{"/prod"}, ssmconfig.PolicyOptions{})
+		if err != nil {
+			t.Fatalf("Policy() unexpected error: %v", err)
+		}
+		b, err := json.Marshal(policy)
+		if err != nil {
+			t.Fatalf("json.Marshal() unexpected error: %v", err)
+		}
+		want := `{"Version":"2012-10-17","Statement":[{"Sid":"ReadParameters","Effect":"Allow","Action":["ssm:GetParameters"],"Resource":["arn:aws:ssm:*:*:parameter/prod/region"]}]}`
+		if string(b) != want {
+			t.Errorf("json.Marshal() unexpected JSON:\nwant %s\nhave %s", want, b)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		p := &ssmconfig.Provider{}
+		if _, err := p.Policy(&config{}, nil, opts); err == nil {
+			t.Errorf("Policy() expected error without config paths")
+		}
+		if _, err := p.Policy(config{}, []string{"/prod"}, opts); err == nil {
+			t.Errorf("Policy() expected error for non-pointer")
+		}
+		if _, err := p.Policy(&struct{ A string }{}, []string{"/prod"}, opts); err == nil {
+			t.Errorf("Policy() expected error without tagged fields")
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package ssmconfig
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of requests made to Parameter Store. A RateLimiter can be
+// shared by multiple Providers to keep their combined request rate within the account's
+// Parameter Store throughput quota. It is safe for concurrent use.
+//
+// It is a token bucket: up to Burst requests can be made at once, after which requests
+// are spaced to an average of TPS per second.
+type RateLimiter struct {
+	// TPS is the sustained number of requests allowed per second.
+	TPS float64
+
+	// Burst is the number of requests that can be made at once.
+	Burst int
+
+	// Now returns the current time. If it is nil time.Now is used.
+	Now func() time.Time
+
+	// Sleep waits for d or until ctx is done. If it is nil a timer is used.
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a limiter that allows tps requests per second with bursts of up
+// to burst requests.
+func NewRateLimiter(tps float64, burst int) *RateLimiter {
+	return &RateLimiter{TPS: tps, Burst: burst}
+}
+
+// Wait blocks until a request can be made or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	d := l.reserve()
+	if d <= 0 {
+		return nil
+	}
+	if l.Sleep != nil {
+		return l.Sleep(ctx, d)
+	}
+	return sleep(ctx, d)
+}
+
+// reserve takes a token and returns how long to wait before it can be used.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.TPS <= 0 {
+		return 0
+	}
+
+	burst := float64(l.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	now := time.Now()
+	if l.Now != nil {
+		now = l.Now()
+	}
+	if l.last.IsZero() {
+		l.tokens = burst
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * l.TPS
+		if l.tokens > burst {
+			l.tokens = burst
+		}
+	}
+	l.last = now
+
+	// Tokens may go negative, which reserves time for the waiting requests in order.
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.TPS * float64(time.Second))
+}
@@ -0,0 +1,59 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	now := time.Unix(0, 0)
+	var waits []time.Duration
+
+	l := ssmconfig.NewRateLimiter(10, 2)
+	l.Now = func() time.Time { return now }
+	l.Sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() unexpected error: %v", err)
+		}
+	}
+
+	// The burst is used immediately, then requests are spaced at 10 per second.
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+	if len(waits) != len(want) || waits[0] != want[0] || waits[1] != want[1] {
+		t.Errorf("Wait() want waits %v, have %v", want, waits)
+	}
+
+	// Tokens refill over time, up to the burst.
+	now = now.Add(time.Second)
+	waits = nil
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() unexpected error: %v", err)
+		}
+	}
+	if len(waits) != 0 {
+		t.Errorf("Wait() unexpected waits after refill: %v", waits)
+	}
+}
+
+func TestRateLimiter_Wait_canceled(t *testing.T) {
+	l := ssmconfig.NewRateLimiter(0.001, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("Wait() unexpected error for first token: %v", err)
+	}
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait() want %v, have %v", context.Canceled, err)
+	}
+}
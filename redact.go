@@ -0,0 +1,330 @@
+package ssmconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// RedactedConfig is a printable copy of a config with sensitive values masked. It
+// implements fmt.Stringer, slog.LogValuer, and json.Marshaler, so it can be logged
+// directly:
+//
+//	logger.Info("loaded config", "config", ssmconfig.Redacted(&c))
+//
+// It is a snapshot: later changes to the config are not reflected.
+type RedactedConfig struct {
+	root interface{}
+}
+
+// Redacted returns a printable copy of c with the values of Secret fields and fields
+// tagged `sensitive:"true"` masked. Nested structs, pointers, slices, arrays, and maps are
+// walked, and unexported fields are omitted. Structs without exported fields are printed
+// only if they implement fmt.Stringer, e.g. time.Time, and by their type name otherwise.
+//
+// Use Report.Redacted to also mask fields that were set from SecureString parameters.
+func Redacted(c interface{}) RedactedConfig {
+	return redactedConfig(c, nil)
+}
+
+// Redacted is the same as the package-level Redacted, and also masks the fields that r
+// reports were set from SecureString parameters. c must be the config r describes.
+func (r *Report) Redacted(c interface{}) RedactedConfig {
+	secure := map[string]bool{}
+	for _, f := range r.Fields {
+		if f.Source == SourceParameter && f.Type == ssm.ParameterTypeSecureString {
+			secure[f.Field] = true
+		}
+	}
+	return redactedConfig(c, secure)
+}
+
+// redactedConfig returns a RedactedConfig for c. The top-level struct fields named in
+// secure are masked in addition to sensitive fields.
+func redactedConfig(c interface{}, secure map[string]bool) RedactedConfig {
+	w := redactWalker{visited: map[uintptr]bool{}}
+	v := reflect.ValueOf(c)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		w.visited[v.Pointer()] = true
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		return RedactedConfig{root: w.walkStruct(v, secure)}
+	}
+	return RedactedConfig{root: w.walk(v)}
+}
+
+// String returns the config formatted like the %+v verb.
+func (r RedactedConfig) String() string {
+	var b strings.Builder
+	writeRedacted(&b, r.root)
+	return b.String()
+}
+
+// GoString returns the same as String so that the %#v verb cannot expose values.
+func (r RedactedConfig) GoString() string {
+	return r.String()
+}
+
+// LogValue returns the config as a group, with nested structs and maps as nested groups.
+// Slices are values that text handlers format as by String and JSON handlers as arrays.
+func (r RedactedConfig) LogValue() slog.Value {
+	return logValue(r.root)
+}
+
+// MarshalJSON encodes the config as a JSON object with fields in struct order.
+func (r RedactedConfig) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeJSON(&b, r.root); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Nodes of a redacted value. Leaves are stored as their original values.
+type (
+	redactedStruct []redactedField
+	redactedMap    []redactedField
+	redactedSlice  []interface{}
+	redactedMask   struct{}
+)
+
+type redactedField struct {
+	name  string
+	value interface{}
+}
+
+// redactWalker converts values into redacted nodes.
+type redactWalker struct {
+	// visited holds the pointers being walked, to stop at cycles.
+	visited map[uintptr]bool
+}
+
+func (w redactWalker) walk(v reflect.Value) interface{} {
+	if v.IsValid() && v.Type().Implements(secretValueType) {
+		return redactedMask{}
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			if w.visited[v.Pointer()] {
+				return "<cycle>"
+			}
+			w.visited[v.Pointer()] = true
+			defer delete(w.visited, v.Pointer())
+		}
+		return w.walk(v.Elem())
+	case reflect.Struct:
+		return w.walkStruct(v, nil)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return leaf(v)
+		}
+		s := make(redactedSlice, v.Len())
+		for i := range s {
+			s[i] = w.walk(v.Index(i))
+		}
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(redactedMap, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m = append(m, redactedField{name: fmt.Sprint(leaf(iter.Key())), value: w.walk(iter.Value())})
+		}
+		sort.Slice(m, func(i, j int) bool { return m[i].name < m[j].name })
+		return m
+	default:
+		return leaf(v)
+	}
+}
+
+// walkStruct converts a struct. The fields named in secure are masked in addition to
+// sensitive fields.
+func (w redactWalker) walkStruct(v reflect.Value, secure map[string]bool) interface{} {
+	t := v.Type()
+
+	var s redactedStruct
+	exported := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		exported = true
+
+		if secure[f.Name] || sensitiveTag(f.Tag) {
+			s = append(s, redactedField{name: f.Name, value: redactedMask{}})
+			continue
+		}
+		s = append(s, redactedField{name: f.Name, value: w.walk(v.Field(i))})
+	}
+	if !exported {
+		// Structs without exported fields are only printed if they format themselves, e.g.
+		// time.Time, as printing them as they are would expose their unexported fields.
+		if v.CanInterface() {
+			if _, ok := v.Interface().(fmt.Stringer); ok {
+				return leaf(v)
+			}
+		}
+		return "<" + t.String() + ">"
+	}
+	return s
+}
+
+// sensitiveTag reports whether tag contains `sensitive:"true"`.
+func sensitiveTag(tag reflect.StructTag) bool {
+	b, _ := strconv.ParseBool(tag.Get("sensitive"))
+	return b
+}
+
+// leaf returns the value of v, or its type if it cannot be accessed.
+func leaf(v reflect.Value) interface{} {
+	if !v.CanInterface() {
+		return "<" + v.Type().String() + ">"
+	}
+	return v.Interface()
+}
+
+func writeRedacted(b *strings.Builder, node interface{}) {
+	switch node := node.(type) {
+	case redactedStruct:
+		b.WriteByte('{')
+		writeFields(b, node)
+		b.WriteByte('}')
+	case redactedMap:
+		b.WriteString("map[")
+		writeFields(b, node)
+		b.WriteByte(']')
+	case redactedSlice:
+		b.WriteByte('[')
+		for i, v := range node {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			writeRedacted(b, v)
+		}
+		b.WriteByte(']')
+	case redactedMask:
+		b.WriteString(redacted)
+	case nil:
+		b.WriteString("<nil>")
+	default:
+		fmt.Fprintf(b, "%+v", node)
+	}
+}
+
+func writeFields(b *strings.Builder, fields []redactedField) {
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(f.name)
+		b.WriteByte(':')
+		writeRedacted(b, f.value)
+	}
+}
+
+func writeJSON(b *bytes.Buffer, node interface{}) error {
+	var fields []redactedField
+	switch node := node.(type) {
+	case redactedStruct:
+		fields = node
+	case redactedMap:
+		fields = node
+	case redactedSlice:
+		b.WriteByte('[')
+		for i, v := range node {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeJSON(b, v); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+		return nil
+	case redactedMask:
+		return writeJSON(b, redacted)
+	default:
+		enc, err := json.Marshal(node)
+		if err != nil {
+			return err
+		}
+		b.Write(enc)
+		return nil
+	}
+
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		b.Write(name)
+		b.WriteByte(':')
+		if err := writeJSON(b, f.value); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+func logValue(node interface{}) slog.Value {
+	var fields []redactedField
+	switch node := node.(type) {
+	case redactedStruct:
+		fields = node
+	case redactedMap:
+		fields = node
+	case redactedSlice:
+		return slog.AnyValue(redactedList(node))
+	case redactedMask:
+		return slog.StringValue(redacted)
+	default:
+		return slog.AnyValue(node)
+	}
+
+	attrs := make([]slog.Attr, len(fields))
+	for i, f := range fields {
+		attrs[i] = slog.Attr{Key: f.name, Value: logValue(f.value)}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// redactedList is a redactedSlice in a slog.Value. slog has no kind for lists, so handlers
+// format it with String or MarshalJSON.
+type redactedList redactedSlice
+
+func (l redactedList) String() string {
+	var b strings.Builder
+	writeRedacted(&b, redactedSlice(l))
+	return b.String()
+}
+
+func (l redactedList) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeJSON(&b, redactedSlice(l)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,161 @@
+package ssmconfig_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+type redactDatabase struct {
+	Host     string
+	Password string `sensitive:"true"`
+}
+
+type redactConfig struct {
+	Name      string
+	Port      int
+	Token     string `sensitive:"true"`
+	Database  redactDatabase
+	Replicas  []*redactDatabase
+	Labels    map[string]string
+	Secrets   map[string]redactDatabase
+	Empty     *redactDatabase
+	unexposed string
+}
+
+func newRedactConfig() *redactConfig {
+	return &redactConfig{
+		Name:     "service",
+		Port:     8080,
+		Token:    "token-secret",
+		Database: redactDatabase{Host: "db", Password: "db-secret"},
+		Replicas: []*redactDatabase{{Host: "replica", Password: "replica-secret"}},
+		Labels:   map[string]string{"b": "2", "a": "1"},
+		Secrets:  map[string]redactDatabase{"x": {Host: "x", Password: "map-secret"}},
+
+		unexposed: "unexposed-secret",
+	}
+}
+
+func assertRedacted(t *testing.T, name, s string) {
+	t.Helper()
+	for _, secret := range []string{"token-secret", "db-secret", "replica-secret", "map-secret", "unexposed-secret"} {
+		if strings.Contains(s, secret) {
+			t.Errorf("%s exposes %q: %s", name, secret, s)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	r := ssmconfig.Redacted(newRedactConfig())
+
+	want := "{Name:service Port:8080 Token:[REDACTED] Database:{Host:db Password:[REDACTED]} " +
+		"Replicas:[{Host:replica Password:[REDACTED]}] Labels:map[a:1 b:2] " +
+		"Secrets:map[x:{Host:x Password:[REDACTED]}] Empty:<nil>}"
+	if have := r.String(); have != want {
+		t.Errorf("String() want %s, have %s", want, have)
+	}
+
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+		assertRedacted(t, verb, fmt.Sprintf(verb, r))
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("MarshalJSON() unexpected error: %v", err)
+	}
+	assertRedacted(t, "MarshalJSON()", string(b))
+	if !strings.HasPrefix(string(b), `{"Name":"service","Port":8080,"Token":"[REDACTED]"`) {
+		t.Errorf("MarshalJSON() unexpected output: %s", b)
+	}
+
+	for _, tc := range []struct {
+		handler         func(*bytes.Buffer) slog.Handler
+		group, replicas string
+	}{{
+		handler:  func(buf *bytes.Buffer) slog.Handler { return slog.NewTextHandler(buf, nil) },
+		group:    "config.Database.Host=db",
+		replicas: `config.Replicas="[{Host:replica Password:[REDACTED]}]"`,
+	}, {
+		handler:  func(buf *bytes.Buffer) slog.Handler { return slog.NewJSONHandler(buf, nil) },
+		group:    `"Database":{"Host":"db"`,
+		replicas: `"Replicas":[{"Host":"replica","Password":"[REDACTED]"}]`,
+	}} {
+		var buf bytes.Buffer
+		slog.New(tc.handler(&buf)).Info("loaded config", "config", r)
+		assertRedacted(t, "slog", buf.String())
+		if !strings.Contains(buf.String(), tc.group) {
+			t.Errorf("slog output missing nested group %s: %s", tc.group, buf.String())
+		}
+		if !strings.Contains(buf.String(), tc.replicas) {
+			t.Errorf("slog output missing slice %s: %s", tc.replicas, buf.String())
+		}
+	}
+}
+
+func TestReport_Redacted(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.PutSecure("/base/strings/secret", "hunter2")
+
+	type config struct {
+		S1     string `ssm:"/strings/s1"`
+		Secret string `ssm:"/strings/secret"`
+	}
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+
+	var c config
+	report, err := p.ProcessWithReport(context.Background(), "/base", &c)
+	if err != nil {
+		t.Fatalf("ProcessWithReport() unexpected error: %v", err)
+	}
+
+	if have, want := report.Redacted(&c).String(), "{S1:string1 Secret:[REDACTED]}"; have != want {
+		t.Errorf("Redacted() want %s, have %s", want, have)
+	}
+	if have, want := ssmconfig.Redacted(&c).String(), "{S1:string1 Secret:hunter2}"; have != want {
+		t.Errorf("Redacted() want %s, have %s", want, have)
+	}
+}
+
+type redactCredentials struct {
+	password string
+}
+
+func TestRedacted_opaqueStruct(t *testing.T) {
+	c := struct {
+		Created     time.Time
+		Credentials redactCredentials
+	}{
+		Created:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Credentials: redactCredentials{password: "topsecret"},
+	}
+
+	want := "{Created:2020-01-01 00:00:00 +0000 UTC Credentials:<ssmconfig_test.redactCredentials>}"
+	if have := ssmconfig.Redacted(&c).String(); have != want {
+		t.Errorf("String() want %s, have %s", want, have)
+	}
+}
+
+func TestRedacted_cycle(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	n := &node{Name: "a"}
+	n.Next = n
+
+	if have, want := ssmconfig.Redacted(n).String(), "{Name:a Next:<cycle>}"; have != want {
+		t.Errorf("String() want %s, have %s", want, have)
+	}
+}
@@ -0,0 +1,96 @@
+package ssmconfig
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// Report describes where the values of a config came from. It is returned by
+// Provider.ProcessWithReport and can be encoded as JSON, e.g. for a debug endpoint.
+//
+// A Report never contains parameter values.
+type Report struct {
+	// ConfigPath is the base path the config was processed with.
+	ConfigPath string `json:"configPath"`
+
+	// Fields describes each struct field with an `ssm` tag, in field order.
+	Fields []FieldReport `json:"fields"`
+
+	// Stale reports whether last-known-good values were used because parameters could not
+	// be fetched from Parameter Store.
+	Stale bool `json:"stale"`
+
+	// SavedAt is when the oldest last-known-good value used was fetched. It is only set if
+	// Stale is true.
+	SavedAt *time.Time `json:"savedAt,omitempty"`
+
+	// Cached is the number of parameters served from the Provider's cache.
+	Cached int `json:"cached"`
+
+	// Requests is the number of requests made to Parameter Store, including retries.
+	Requests int `json:"requests"`
+
+	// Retries is the number of requests that were retries.
+	Retries int `json:"retries"`
+}
+
+// FieldReport describes where the value of a struct field came from.
+type FieldReport struct {
+	// Field is the name of the struct field.
+	Field string `json:"field"`
+
+	// Parameter is the resolved name of the field's parameter.
+	Parameter string `json:"parameter"`
+
+	Source Source `json:"source"`
+
+	// The following describe the parameter, and are only set if Source is
+	// SourceParameter.
+	Type             string     `json:"type,omitempty"`
+	Version          int64      `json:"version,omitempty"`
+	LastModifiedDate *time.Time `json:"lastModifiedDate,omitempty"`
+	ARN              string     `json:"arn,omitempty"`
+}
+
+func newReport(configPath string, res fetchResult, stale *StaleError) *Report {
+	r := &Report{
+		ConfigPath: configPath,
+		Cached:     res.cached,
+		Requests:   res.requests,
+		Retries:    res.retries,
+	}
+	if stale != nil {
+		r.Stale = true
+		r.SavedAt = aws.Time(stale.SavedAt)
+	}
+	return r
+}
+
+// addField adds the resolution of a field to the report. param is the field's parameter,
+// if it was fetched.
+func (r *Report) addField(info FieldInfo, param *ssm.Parameter) {
+	f := FieldReport{
+		Field:     info.Field,
+		Parameter: info.Parameter,
+		Source:    info.Source,
+	}
+	if info.Source == SourceParameter && param != nil {
+		f.Type = aws.StringValue(param.Type)
+		f.Version = aws.Int64Value(param.Version)
+		f.LastModifiedDate = param.LastModifiedDate
+		f.ARN = aws.StringValue(param.ARN)
+	}
+	r.Fields = append(r.Fields, f)
+}
+
+// Field returns the report for the struct field with the given name.
+func (r *Report) Field(name string) (FieldReport, bool) {
+	for _, f := range r.Fields {
+		if f.Field == name {
+			return f, true
+		}
+	}
+	return FieldReport{}, false
+}
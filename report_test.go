@@ -0,0 +1,113 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ssm"
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_ProcessWithReport(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.PutSecure("/base/strings/secret", "hunter2")
+
+	type config struct {
+		S1     string `ssm:"/strings/s1"`
+		S2     string `ssm:"/strings/s2" default:"string2"`
+		S3     string `ssm:"/strings/s3"`
+		Secret string `ssm:"/strings/secret"`
+	}
+
+	p := &ssmconfig.Provider{
+		SSM:           s.Client(),
+		LastKnownGood: ssmconfig.NewDiskCache(filepath.Join(t.TempDir(), "cache.json"), []byte("0123456789abcdef")),
+	}
+
+	var c config
+	report, err := p.ProcessWithReport(context.Background(), "/base", &c)
+	if err != nil {
+		t.Fatalf("ProcessWithReport() unexpected error: %v", err)
+	}
+
+	if report.ConfigPath != "/base" || report.Stale || report.Requests != 1 || len(report.Fields) != 4 {
+		t.Fatalf("ProcessWithReport() unexpected report: %+v", report)
+	}
+
+	s1, _ := report.Field("S1")
+	param, _ := s.Get("/base/strings/s1")
+	if s1.Source != ssmconfig.SourceParameter || s1.Parameter != "/base/strings/s1" ||
+		s1.Type != ssm.ParameterTypeString || s1.Version != 1 || s1.ARN != param.ARN() ||
+		s1.LastModifiedDate == nil || s1.LastModifiedDate.Sub(param.LastModifiedDate).Abs() > time.Second {
+		t.Errorf("ProcessWithReport() unexpected S1 report: %+v", s1)
+	}
+
+	if s2, _ := report.Field("S2"); s2.Source != ssmconfig.SourceDefault || s2.Type != "" || s2.Version != 0 {
+		t.Errorf("ProcessWithReport() unexpected S2 report: %+v", s2)
+	}
+	if s3, _ := report.Field("S3"); s3.Source != ssmconfig.SourceAbsent {
+		t.Errorf("ProcessWithReport() unexpected S3 report: %+v", s3)
+	}
+	if secret, _ := report.Field("Secret"); secret.Type != ssm.ParameterTypeSecureString {
+		t.Errorf("ProcessWithReport() unexpected Secret report: %+v", secret)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(report)
+		if err != nil {
+			t.Fatalf("Marshal() unexpected error: %v", err)
+		}
+		if strings.Contains(string(b), "hunter2") || strings.Contains(string(b), "string1") {
+			t.Errorf("report exposes values: %s", b)
+		}
+		if !strings.Contains(string(b), `"source":"default"`) {
+			t.Errorf("report does not encode source as a name: %s", b)
+		}
+
+		var decoded ssmconfig.Report
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf("Unmarshal() unexpected error: %v", err)
+		}
+		if decoded.Fields[1].Source != ssmconfig.SourceDefault {
+			t.Errorf("Unmarshal() want source default, have %v", decoded.Fields[1].Source)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		s.Fail("GetParameters", "InternalServerError", 1)
+
+		report, err := p.ProcessWithReport(context.Background(), "/base", &c)
+		if _, ok := err.(*ssmconfig.StaleError); !ok {
+			t.Fatalf("ProcessWithReport() want *StaleError, have %v", err)
+		}
+		if !report.Stale || report.SavedAt == nil {
+			t.Errorf("ProcessWithReport() want stale report, have %+v", report)
+		}
+		if s1, _ := report.Field("S1"); s1.Source != ssmconfig.SourceParameter || s1.Version != 1 {
+			t.Errorf("ProcessWithReport() unexpected S1 report: %+v", s1)
+		}
+	})
+
+	t.Run("required", func(t *testing.T) {
+		type config struct {
+			S1 string `ssm:"/strings/s1"`
+			S3 string `ssm:"/strings/s3" required:"true"`
+		}
+
+		var c config
+		report, err := p.ProcessWithReport(context.Background(), "/base", &c)
+		if err == nil {
+			t.Fatalf("ProcessWithReport() expected error")
+		}
+		if report == nil || len(report.Fields) != 2 || report.Fields[1].Source != ssmconfig.SourceAbsent {
+			t.Errorf("ProcessWithReport() want partial report, have %+v", report)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package ssmconfig
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// RetryPolicy configures how a Provider retries requests to Parameter Store that fail
+// with throttling or transient errors. It is applied independently of any retries made by
+// the SDK client.
+//
+// Delays grow exponentially from BaseDelay up to MaxDelay, and each delay is chosen at
+// random between zero and its maximum ("full jitter") so that many clients starting at
+// once do not retry in lockstep.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts made for each request, including the
+	// first. If it is less than 2 requests are not retried.
+	MaxAttempts int
+
+	// BaseDelay is the maximum delay before the first retry.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay before any retry. If it is zero delays are not capped.
+	MaxDelay time.Duration
+
+	// Sleep waits for d or until ctx is done. If it is nil a timer is used.
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewRetryPolicy returns a policy that makes up to maxAttempts attempts with delays
+// starting at 100ms and capped at 5s.
+func NewRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+// RetryError is returned when a request to Parameter Store failed after being retried.
+type RetryError struct {
+	// Err is the error returned by the last attempt.
+	Err error
+
+	// Attempts is the number of attempts made.
+	Attempts int
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Cause returns the error returned by the last attempt.
+func (e *RetryError) Cause() error { return e.Err }
+
+// Unwrap returns the error returned by the last attempt.
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// retryable reports whether err is a throttling or transient error that may succeed if the
+// request is retried.
+func retryable(err error) bool {
+	if request.IsErrorThrottle(err) || request.IsErrorRetryable(err) {
+		return true
+	}
+	if rerr, ok := err.(awserr.RequestFailure); ok {
+		return rerr.StatusCode() >= 500
+	}
+	return false
+}
+
+// do calls fn until it succeeds, returns an error that is not retryable, or the policy's
+// attempts are exhausted. The number of retries made is returned. A nil policy calls fn
+// once.
+func (rp *RetryPolicy) do(ctx context.Context, fn func() error) (retries int, err error) {
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || rp == nil || attempt >= rp.MaxAttempts || !retryable(err) {
+			if err != nil && attempt > 1 {
+				err = &RetryError{Err: err, Attempts: attempt}
+			}
+			return attempt - 1, err
+		}
+
+		if serr := rp.sleep(ctx, rp.delay(attempt)); serr != nil {
+			return attempt - 1, &RetryError{Err: err, Attempts: attempt}
+		}
+	}
+}
+
+// delay returns the delay before the retry following the given attempt.
+func (rp *RetryPolicy) delay(attempt int) time.Duration {
+	max := rp.BaseDelay
+	for i := 1; i < attempt && (rp.MaxDelay == 0 || max < rp.MaxDelay); i++ {
+		max *= 2
+	}
+	if rp.MaxDelay > 0 && max > rp.MaxDelay {
+		max = rp.MaxDelay
+	}
+	if max <= 0 {
+		return 0
+	}
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	if rp.rand == nil {
+		rp.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return time.Duration(rp.rand.Int63n(int64(max) + 1))
+}
+
+func (rp *RetryPolicy) sleep(ctx context.Context, d time.Duration) error {
+	if rp.Sleep != nil {
+		return rp.Sleep(ctx, d)
+	}
+	return sleep(ctx, d)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_Process_retry(t *testing.T) {
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+	}
+
+	newProvider := func(s *ssmtest.Server) (*ssmconfig.Provider, *[]time.Duration) {
+		var delays []time.Duration
+		retry := ssmconfig.NewRetryPolicy(3)
+		retry.Sleep = func(_ context.Context, d time.Duration) error {
+			delays = append(delays, d)
+			return nil
+		}
+		return &ssmconfig.Provider{SSM: s.Client(), Retry: retry}, &delays
+	}
+
+	t.Run("throttled then succeeds", func(t *testing.T) {
+		s := ssmtest.NewServer()
+		defer s.Close()
+		s.Put("/base/strings/s1", "string1")
+		s.Fail("GetParameters", "ThrottlingException", 1)
+		s.Fail("GetParameters", "InternalServerError", 1)
+
+		p, delays := newProvider(s)
+
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c.S1 != "string1" {
+			t.Errorf("Process() want %q, have %q", "string1", c.S1)
+		}
+		if calls := s.Calls("GetParameters"); calls != 3 {
+			t.Errorf("Process() want 3 calls, have %d", calls)
+		}
+		if len(*delays) != 2 {
+			t.Fatalf("Process() want 2 delays, have %v", *delays)
+		}
+		if (*delays)[0] > p.Retry.BaseDelay || (*delays)[1] > 2*p.Retry.BaseDelay {
+			t.Errorf("Process() delays exceed backoff: %v", *delays)
+		}
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		s := ssmtest.NewServer()
+		defer s.Close()
+		s.Fail("GetParameters", "ThrottlingException", 5)
+
+		p, _ := newProvider(s)
+
+		var c config
+		err := p.Process("/base", &c)
+
+		var rerr *ssmconfig.RetryError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("Process() want *RetryError, have %v", err)
+		}
+		if rerr.Attempts != 3 {
+			t.Errorf("Process() want 3 attempts, have %d", rerr.Attempts)
+		}
+		if calls := s.Calls("GetParameters"); calls != 3 {
+			t.Errorf("Process() want 3 calls, have %d", calls)
+		}
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		s := ssmtest.NewServer()
+		defer s.Close()
+		s.Fail("GetParameters", "AccessDeniedException", 1)
+
+		p, delays := newProvider(s)
+
+		var c config
+		err := p.Process("/base", &c)
+		if err == nil {
+			t.Fatalf("Process() expected error")
+		}
+
+		var rerr *ssmconfig.RetryError
+		if errors.As(err, &rerr) {
+			t.Errorf("Process() unexpected *RetryError: %v", err)
+		}
+		if len(*delays) != 0 {
+			t.Errorf("Process() unexpected retries: %v", *delays)
+		}
+	})
+
+	t.Run("nil policy", func(t *testing.T) {
+		s := ssmtest.NewServer()
+		defer s.Close()
+		s.Fail("GetParameters", "ThrottlingException", 1)
+
+		p := &ssmconfig.Provider{SSM: s.Client()}
+
+		var c config
+		if err := p.Process("/base", &c); err == nil {
+			t.Errorf("Process() expected error")
+		}
+		if calls := s.Calls("GetParameters"); calls != 1 {
+			t.Errorf("Process() want 1 call, have %d", calls)
+		}
+	})
+
+	t.Run("batches", func(t *testing.T) {
+		s := ssmtest.NewServer()
+		defer s.Close()
+
+		type config struct {
+			S01 string `ssm:"/s01"`
+			S02 string `ssm:"/s02"`
+			S03 string `ssm:"/s03"`
+			S04 string `ssm:"/s04"`
+			S05 string `ssm:"/s05"`
+			S06 string `ssm:"/s06"`
+			S07 string `ssm:"/s07"`
+			S08 string `ssm:"/s08"`
+			S09 string `ssm:"/s09"`
+			S10 string `ssm:"/s10"`
+			S11 string `ssm:"/s11"`
+		}
+		s.Put("/base/s11", "string11")
+
+		p := &ssmconfig.Provider{SSM: s.Client()}
+
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c.S11 != "string11" {
+			t.Errorf("Process() want %q, have %q", "string11", c.S11)
+		}
+		if calls := s.Calls("GetParameters"); calls != 2 {
+			t.Errorf("Process() want 2 calls, have %d", calls)
+		}
+	})
+}
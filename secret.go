@@ -0,0 +1,122 @@
+package ssmconfig
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Secret holds a value that must not be disclosed accidentally. Formatting a Secret with
+// fmt, encoding it as JSON, or logging it with log/slog always produces a mask rather than
+// the value. The value is only available through Reveal.
+//
+// Secret fields are populated by Provider.Process like any other field, so T may be any
+// supported type, e.g. Secret[string] or Secret[[]byte]. Errors decoding a Secret never
+// include the value.
+//
+//	type Config struct {
+//		Password ssmconfig.Secret[string] `ssm:"/db/password"`
+//	}
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret returns a Secret holding v.
+func NewSecret[T any](v T) Secret[T] {
+	return Secret[T]{value: v}
+}
+
+// Reveal returns the value of the secret.
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// Zero clears the secret. If T is a byte slice its backing array is overwritten with
+// zeros first, so the value does not remain in memory. Copies returned by Reveal are not
+// affected.
+func (s *Secret[T]) Zero() {
+	if b, ok := any(&s.value).(*[]byte); ok {
+		for i := range *b {
+			(*b)[i] = 0
+		}
+	}
+	var zero T
+	s.value = zero
+}
+
+// String returns a mask.
+func (s Secret[T]) String() string {
+	return redacted
+}
+
+// GoString returns a mask.
+func (s Secret[T]) GoString() string {
+	return redacted
+}
+
+// Format writes a mask for every verb.
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	_, _ = f.Write([]byte(redacted))
+}
+
+// MarshalJSON encodes a mask.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// LogValue returns a mask.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// decodeSecret decodes str into the value of the secret. The error does not include str.
+func (s *Secret[T]) decodeSecret(str string) error {
+	v := reflect.ValueOf(&s.value).Elem()
+	if err := setValue(v, str); err != nil {
+		return errors.Errorf("could not decode secret value into type %v", v.Type())
+	}
+	return nil
+}
+
+// encodeSecret encodes the value of the secret. The error does not include the value.
+func (s Secret[T]) encodeSecret() (string, error) {
+	v := reflect.ValueOf(&s.value).Elem()
+	str, err := encodeValue(v)
+	if err != nil {
+		return "", errors.Errorf("could not encode secret value of type %v", v.Type())
+	}
+	return str, nil
+}
+
+func (s Secret[T]) secret() {}
+
+// secretDecoder is implemented by pointers to Secrets.
+type secretDecoder interface {
+	decodeSecret(s string) error
+}
+
+// secretEncoder is implemented by Secrets.
+type secretEncoder interface {
+	encodeSecret() (string, error)
+}
+
+// secretValue is implemented by Secrets.
+type secretValue interface {
+	secret()
+}
+
+var (
+	secretDecoderType = reflect.TypeOf((*secretDecoder)(nil)).Elem()
+	secretEncoderType = reflect.TypeOf((*secretEncoder)(nil)).Elem()
+	secretValueType   = reflect.TypeOf((*secretValue)(nil)).Elem()
+)
+
+func decodeSecret(v reflect.Value, s string) error {
+	return v.Addr().Interface().(secretDecoder).decodeSecret(s)
+}
+
+func encodeSecret(v reflect.Value) (string, error) {
+	return v.Interface().(secretEncoder).encodeSecret()
+}
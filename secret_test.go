@@ -0,0 +1,106 @@
+package ssmconfig_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestSecret(t *testing.T) {
+	s := ssmconfig.NewSecret("hunter2")
+
+	if s.Reveal() != "hunter2" {
+		t.Errorf("Reveal() want %q, have %q", "hunter2", s.Reveal())
+	}
+
+	type wrapper struct {
+		Password ssmconfig.Secret[string]
+		Pointer  *ssmconfig.Secret[string]
+	}
+	w := wrapper{Password: s, Pointer: &s}
+
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		if out := fmt.Sprintf(verb, s); strings.Contains(out, "hunter2") || strings.Contains(out, fmt.Sprintf(verb, "hunter2")) {
+			t.Errorf("Sprintf(%q) exposes value: %s", verb, out)
+		}
+		if out := fmt.Sprintf(verb, w); strings.Contains(out, "hunter2") {
+			t.Errorf("Sprintf(%q) of struct exposes value: %s", verb, out)
+		}
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+	if want := `{"Password":"[REDACTED]","Pointer":"[REDACTED]"}`; string(b) != want {
+		t.Errorf("Marshal() want %s, have %s", want, b)
+	}
+
+	if out := ssmconfig.Redacted(&w).String(); strings.Contains(out, "hunter2") {
+		t.Errorf("Redacted() exposes value: %s", out)
+	}
+}
+
+func TestSecret_Zero(t *testing.T) {
+	b := []byte("hunter2")
+	s := ssmconfig.NewSecret(b)
+
+	s.Zero()
+	if s.Reveal() != nil {
+		t.Errorf("Zero() want nil value, have %q", s.Reveal())
+	}
+	for _, c := range b {
+		if c != 0 {
+			t.Fatalf("Zero() did not clear backing bytes: %q", b)
+		}
+	}
+
+	str := ssmconfig.NewSecret("hunter2")
+	str.Zero()
+	if str.Reveal() != "" {
+		t.Errorf("Zero() want empty value, have %q", str.Reveal())
+	}
+}
+
+func TestProvider_Process_secret(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.PutSecure("/base/secret", "hunter2")
+	s.Put("/base/bytes", "bytes")
+	s.Put("/base/port", "not-a-port-hunter3")
+
+	type config struct {
+		Password ssmconfig.Secret[string] `ssm:"/secret"`
+		Key      ssmconfig.Secret[[]byte] `ssm:"/secret"`
+		Bytes    []byte                   `ssm:"/bytes"`
+	}
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+
+	var c config
+	if err := p.Process("/base", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	if c.Password.Reveal() != "hunter2" || string(c.Key.Reveal()) != "hunter2" || string(c.Bytes) != "bytes" {
+		t.Errorf("Process() unexpected values: %q %q %q", c.Password.Reveal(), c.Key.Reveal(), c.Bytes)
+	}
+
+	t.Run("decode error", func(t *testing.T) {
+		type config struct {
+			Port ssmconfig.Secret[int] `ssm:"/port"`
+		}
+
+		var c config
+		err := p.Process("/base", &c)
+		if err == nil {
+			t.Fatalf("Process() expected error")
+		}
+		if strings.Contains(err.Error(), "hunter3") {
+			t.Errorf("Process() error exposes value: %v", err)
+		}
+	})
+}
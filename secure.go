@@ -0,0 +1,98 @@
+package ssmconfig
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// SecurePolicy is a Provider-wide policy for how sensitive parameters must be stored.
+//
+// Independently of the policy, a field tagged `secure:"true"` must be set from a
+// SecureString parameter, or processing fails. A secure field whose parameter does not
+// exist is not affected and can use its default value.
+type SecurePolicy struct {
+	// Sensitive requires fields tagged `sensitive:"true"` and Secret fields to be set from
+	// SecureString parameters, as if they were tagged `secure:"true"`.
+	Sensitive bool
+
+	// AllowedKeyIDs, if not empty, requires the parameters of secure fields to be
+	// encrypted with one of the listed KMS keys. Keys are compared with the KeyId reported
+	// by DescribeParameters, e.g. "alias/aws/ssm", so the ssm:DescribeParameters permission
+	// is required.
+	//
+	// Keys are not verified when last-known-good values are used.
+	AllowedKeyIDs []string
+}
+
+// requiresSecure reports whether the parameter of field must be a SecureString.
+func (p *Provider) requiresSecure(field *fieldPlan) bool {
+	return field.secure || (p.Secure != nil && p.Secure.Sensitive && field.sensitive)
+}
+
+// checkSecure returns an error if the parameter of field is not a SecureString but is
+// required to be.
+func (p *Provider) checkSecure(field fieldSpec, param *ssm.Parameter) error {
+	if !p.requiresSecure(field.fieldPlan) || aws.StringValue(param.Type) == ssm.ParameterTypeSecureString {
+		return nil
+	}
+	return errors.Errorf(
+		"ssmconfig: %s must be a SecureString parameter for field %s, but is a %s",
+		field.name, field.field, aws.StringValue(param.Type),
+	)
+}
+
+// checkKeyIDs verifies that the parameters of the secure fields in spec that are present
+// in params are encrypted with an allowed key. If metadata is nil the parameters are
+// described.
+func (p *Provider) checkKeyIDs(
+	ctx context.Context,
+	spec structSpec,
+	params map[string]*ssm.Parameter,
+	metadata map[string]*ssm.ParameterMetadata,
+) error {
+	if p.Secure == nil || len(p.Secure.AllowedKeyIDs) == 0 {
+		return nil
+	}
+
+	var names []string
+	for _, field := range spec {
+		if _, ok := params[field.name]; ok && p.requiresSecure(field.fieldPlan) {
+			names = append(names, field.name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	if metadata == nil {
+		var err error
+		if metadata, err = p.describeParameters(ctx, names); err != nil {
+			return errors.Wrap(err, "ssmconfig: could not describe parameters to verify their keys")
+		}
+	}
+
+	for _, name := range names {
+		m, ok := metadata[name]
+		if !ok {
+			return errors.Errorf("ssmconfig: could not verify the key of %s", name)
+		}
+
+		keyID := aws.StringValue(m.KeyId)
+		if !p.Secure.allowedKeyID(keyID) {
+			return errors.Errorf("ssmconfig: %s is encrypted with key %q, which is not allowed", name, keyID)
+		}
+	}
+	return nil
+}
+
+func (sp *SecurePolicy) allowedKeyID(keyID string) bool {
+	for _, allowed := range sp.AllowedKeyIDs {
+		if keyID == allowed {
+			return true
+		}
+	}
+	return false
+}
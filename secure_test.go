@@ -0,0 +1,126 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestProvider_Process_secure(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/plain", "plain-password")
+	s.PutSecure("/base/secure", "hunter2")
+	s.Set(ssmtest.Parameter{Name: "/base/custom", Value: "hunter3", Type: "SecureString", KeyID: "alias/custom"})
+
+	t.Run("secure tag", func(t *testing.T) {
+		type config struct {
+			Secure  string `ssm:"/secure" secure:"true"`
+			Missing string `ssm:"/missing" secure:"true" default:"default"`
+		}
+		p := &ssmconfig.Provider{SSM: s.Client()}
+
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c.Secure != "hunter2" || c.Missing != "default" {
+			t.Errorf("Process() unexpected values: %+v", c)
+		}
+
+		type insecureConfig struct {
+			Plain string `ssm:"/plain" secure:"true"`
+		}
+		var ic insecureConfig
+		err := p.Process("/base", &ic)
+		if err == nil || !strings.Contains(err.Error(), "must be a SecureString") {
+			t.Errorf("Process() want SecureString error, have %v", err)
+		}
+		if ic.Plain != "" {
+			t.Errorf("Process() set insecure field: %q", ic.Plain)
+		}
+	})
+
+	t.Run("sensitive policy", func(t *testing.T) {
+		type config struct {
+			Tagged string                   `ssm:"/plain" sensitive:"true"`
+			Secret ssmconfig.Secret[string] `ssm:"/plain"`
+		}
+
+		p := &ssmconfig.Provider{SSM: s.Client()}
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error without policy: %v", err)
+		}
+
+		p.Secure = &ssmconfig.SecurePolicy{Sensitive: true}
+		type taggedConfig struct {
+			Tagged string `ssm:"/plain" sensitive:"true"`
+		}
+		if err := p.Process("/base", &taggedConfig{}); err == nil {
+			t.Errorf("Process() expected error for sensitive field")
+		}
+		type secretConfig struct {
+			Secret ssmconfig.Secret[string] `ssm:"/plain"`
+		}
+		if err := p.Process("/base", &secretConfig{}); err == nil {
+			t.Errorf("Process() expected error for Secret field")
+		}
+	})
+
+	t.Run("allowed keys", func(t *testing.T) {
+		type config struct {
+			Secure string `ssm:"/secure" secure:"true"`
+			Custom string `ssm:"/custom" secure:"true"`
+		}
+
+		p := &ssmconfig.Provider{
+			SSM:    s.Client(),
+			Secure: &ssmconfig.SecurePolicy{AllowedKeyIDs: []string{ssmtest.DefaultKeyID, "alias/custom"}},
+		}
+		var c config
+		if err := p.Process("/base", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+
+		p.Secure.AllowedKeyIDs = []string{"alias/custom"}
+		err := p.Process("/base", &config{})
+		if err == nil || !strings.Contains(err.Error(), ssmtest.DefaultKeyID) {
+			t.Errorf("Process() want key error, have %v", err)
+		}
+	})
+
+	t.Run("watcher", func(t *testing.T) {
+		s.PutSecure("/base/watched", "hunter2")
+
+		type config struct {
+			Watched string `ssm:"/watched" secure:"true"`
+		}
+		p := &ssmconfig.Provider{
+			SSM:    s.Client(),
+			Secure: &ssmconfig.SecurePolicy{AllowedKeyIDs: []string{ssmtest.DefaultKeyID}},
+		}
+
+		var c config
+		w, err := ssmconfig.NewWatcher(context.Background(), p, "/base", &c)
+		if err != nil {
+			t.Fatalf("NewWatcher() unexpected error: %v", err)
+		}
+
+		s.Set(ssmtest.Parameter{Name: "/base/watched", Value: "hunter3", Type: "SecureString", KeyID: "alias/custom"})
+		if err := w.Check(context.Background()); err == nil {
+			t.Errorf("Check() expected key error")
+		}
+
+		s.Put("/base/watched", "plain")
+		if err := w.Check(context.Background()); err == nil {
+			t.Errorf("Check() expected SecureString error")
+		}
+		if have := w.Current().(*config).Watched; have != "hunter2" {
+			t.Errorf("Current() want %q, have %q", "hunter2", have)
+		}
+	})
+}
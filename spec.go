@@ -0,0 +1,221 @@
+package ssmconfig
+
+import (
+	"context"
+	"path"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// typeSpec is the compiled form of a struct type. It does not depend on the base path, so
+// it is built once per type and cached.
+type typeSpec struct {
+	fields []fieldPlan
+}
+
+// fieldPlan is the compiled form of a struct field with an `ssm` tag.
+type fieldPlan struct {
+	index        int    // index of the field in the struct, or in Generated.SSMFields
+	field        string // name of the struct field
+	tag          string // value of the `ssm` tag
+	defaultValue string
+	required     bool
+	decrypt      string     // value of the `decrypt` tag
+	secure       bool       // tagged `secure:"true"`
+	sensitive    bool       // tagged `sensitive:"true"` or a Secret
+	decode       decodeFunc // nil for generated code
+}
+
+// newFieldPlan parses the tags of a struct field.
+func newFieldPlan(index int, field string, tag reflect.StructTag) fieldPlan {
+	return fieldPlan{
+		index:        index,
+		field:        field,
+		tag:          tag.Get("ssm"),
+		defaultValue: tag.Get("default"),
+		required:     tag.Get("required") == "true",
+		decrypt:      tag.Get("decrypt"),
+		secure:       tag.Get("secure") == "true",
+		sensitive:    sensitiveTag(tag),
+	}
+}
+
+// typeSpecs caches the *typeSpec of each struct type.
+var typeSpecs sync.Map // map[reflect.Type]*typeSpec
+
+// compileType returns the *typeSpec for the struct type t.
+func compileType(t reflect.Type) *typeSpec {
+	if ts, ok := typeSpecs.Load(t); ok {
+		return ts.(*typeSpec)
+	}
+
+	ts := &typeSpec{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("ssm")
+		if tag == "" {
+			continue
+		}
+
+		plan := newFieldPlan(i, f.Name, f.Tag)
+		plan.decode = decoderFor(f.Type)
+		plan.sensitive = plan.sensitive || f.Type.Implements(secretValueType)
+		ts.fields = append(ts.fields, plan)
+	}
+
+	actual, _ := typeSpecs.LoadOrStore(t, ts)
+	return actual.(*typeSpec)
+}
+
+// structSpec is a compiled struct type resolved against a base path. It contains a
+// fieldSpec for each struct field with an `ssm` tag.
+type structSpec []fieldSpec
+
+type fieldSpec struct {
+	*fieldPlan
+
+	// name is the full name of the parameter.
+	name string
+}
+
+func buildStructSpec(configPath string, t reflect.Type) structSpec {
+	ts := compileType(t)
+
+	spec := make(structSpec, len(ts.fields))
+	for i := range ts.fields {
+		spec[i] = fieldSpec{
+			fieldPlan: &ts.fields[i],
+			name:      path.Join(configPath, ts.fields[i].tag),
+		}
+	}
+	return spec
+}
+
+// names returns the names of all of the params that need to be requested.
+func (spec structSpec) names() []string {
+	names := make([]string, len(spec))
+	for i := range spec {
+		names[i] = spec[i].name
+	}
+	return names
+}
+
+// requestNames splits the names of the params that need to be requested by whether p
+// requests them with decryption. If only is not nil, names not in only are omitted.
+func (p *Provider) requestNames(spec structSpec, only map[string]struct{}) (decrypted, plain []string) {
+	for i := range spec {
+		if only != nil {
+			if _, ok := only[spec[i].name]; !ok {
+				continue
+			}
+		}
+		if p.decrypts(spec[i].fieldPlan) {
+			decrypted = append(decrypted, spec[i].name)
+		} else {
+			plain = append(plain, spec[i].name)
+		}
+	}
+	return decrypted, plain
+}
+
+// decrypts reports whether p requests the parameter of field with decryption.
+func (p *Provider) decrypts(field *fieldPlan) bool {
+	switch field.decrypt {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return !p.DisableDecryption
+}
+
+// target is a value populated using a structSpec.
+type target interface {
+	// set decodes s into the field described by field.
+	set(field *fieldPlan, s string) error
+}
+
+// structTarget populates a struct using reflection.
+type structTarget struct {
+	v reflect.Value
+}
+
+func (t structTarget) set(field *fieldPlan, s string) error {
+	return field.decode(t.v.Field(field.index), s)
+}
+
+// setField sets the field described by spec[i] to the value of its parameter, or to its
+// default value if the parameter is invalid. The resolution of the field is returned and
+// reported to p's observer and logger.
+func (spec structSpec) setField(
+	ctx context.Context,
+	p *Provider,
+	t target,
+	i int,
+	params map[string]*ssm.Parameter,
+	invalidParams map[string]struct{},
+) (FieldInfo, error) {
+	field := spec[i]
+	info := FieldInfo{Field: field.field, Parameter: field.name}
+	o := p.observer()
+
+	if _, ok := invalidParams[field.name]; ok && field.required {
+		o.FieldResolved(ctx, info)
+		p.logField(ctx, "ssmconfig: required parameter does not exist", field.fieldPlan, info, "", nil)
+		return info, errors.Errorf("ssmconfig: %s is required", field.name)
+	}
+
+	value := field.defaultValue
+	if param, ok := params[field.name]; ok {
+		value = aws.StringValue(param.Value)
+		info.Source = SourceParameter
+		info.ParameterType = aws.StringValue(param.Type)
+		info.Version = aws.Int64Value(param.Version)
+
+		if err := p.checkSecure(field, param); err != nil {
+			o.FieldResolved(ctx, info)
+			p.logField(ctx, "ssmconfig: parameter is not a SecureString", field.fieldPlan, info, redacted, err)
+			return info, err
+		}
+	} else if value != "" {
+		info.Source = SourceDefault
+	}
+
+	if value == "" {
+		o.FieldResolved(ctx, info)
+		p.logField(ctx, "ssmconfig: resolved field", field.fieldPlan, info, value, nil)
+		return info, nil
+	}
+
+	err := t.set(field.fieldPlan, value)
+	if err != nil {
+		// Decode errors include the value, which must not be exposed for SecureStrings or
+		// sensitive fields.
+		switch {
+		case info.ParameterType == ssm.ParameterTypeSecureString:
+			err = errors.Errorf(
+				"ssmconfig: error setting field %s: could not decode the value of SecureString parameter %s",
+				field.field, field.name,
+			)
+		case field.sensitive:
+			err = errors.Errorf(
+				"ssmconfig: error setting field %s: could not decode the value of sensitive field",
+				field.field,
+			)
+		default:
+			err = errors.Wrapf(err, "ssmconfig: error setting field %s", field.field)
+		}
+
+		o.DecodeError(ctx, info)
+		p.logField(ctx, "ssmconfig: could not decode field", field.fieldPlan, info, value, err)
+		return info, err
+	}
+
+	o.FieldResolved(ctx, info)
+	p.logField(ctx, "ssmconfig: resolved field", field.fieldPlan, info, value, nil)
+	return info, nil
+}
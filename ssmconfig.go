@@ -3,9 +3,11 @@
 package ssmconfig
 
 import (
-	"path"
+	"context"
+	"log/slog"
 	"reflect"
-	"strconv"
+	"sort"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -30,11 +32,50 @@ func Process(configPath string, c interface{}) error {
 
 // Provider is a ssm configuration provider.
 type Provider struct {
+	// SSM is the client used to request parameters. Parameters are requested with
+	// GetParametersWithContext, or with GetParameters when the context cannot be canceled,
+	// as with Process.
 	SSM ssmiface.SSMAPI
+
+	// Cache is an optional cache of parameter values shared across calls to Process. When
+	// it is nil every call to Process requests all parameters from Parameter Store.
+	Cache *Cache
+
+	// LastKnownGood is an optional persistent cache of the last successfully fetched
+	// parameters. When Parameter Store cannot be reached the cached values are used and
+	// Process returns a *StaleError.
+	LastKnownGood *DiskCache
+
+	// Retry is an optional policy for retrying requests that fail with throttling or
+	// transient errors. When it is nil failed requests are not retried by the Provider.
+	Retry *RetryPolicy
+
+	// RateLimiter optionally limits the rate of requests to Parameter Store. It can be
+	// shared by multiple Providers.
+	RateLimiter *RateLimiter
+
+	// DisableDecryption requests parameters without decryption by default, so that
+	// kms:Decrypt is not required. The values of SecureString parameters are then the
+	// encrypted ciphertext. Fields tagged `decrypt:"true"` are still decrypted.
+	DisableDecryption bool
+
+	// Secure is an optional policy for how sensitive parameters must be stored.
+	Secure *SecurePolicy
+
+	// Observer optionally receives callbacks as configs are processed.
+	Observer Observer
+
+	// Logger is an optional logger. At debug level it logs how each field is resolved and
+	// failed requests to Parameter Store. SecureString values are always redacted.
+	Logger *slog.Logger
+
+	// flights de-duplicates concurrent fetches of the same parameters.
+	flights flightGroup
 }
 
 // Process loads config values from smm (parameter store) into c. Encrypted parameters
-// will automatically be decrypted. c must be a pointer to a struct.
+// will automatically be decrypted unless decryption is disabled; see the `decrypt` tag. c
+// must be a pointer to a struct.
 //
 // The `ssm` tag is used to lookup the parameter in Parameter Store. It is joined to the
 // provided base path. If the `ssm` tag is missing the struct field will be ignored.
@@ -47,147 +88,454 @@ type Provider struct {
 //
 // The behavior of using the `default` and `required` tags on the same struct field is
 // currently undefined.
+//
+// The `decrypt` tag is used to control whether a parameter is requested with decryption.
+// Parameters are decrypted unless the tag is "false" or p.DisableDecryption is set and the
+// tag is not "true".
+//
+// The `secure` tag is used to require a parameter to be a SecureString. If the parameter
+// exists and is any other type an error will be returned. See also p.Secure.
+//
+// If p.LastKnownGood is set and Parameter Store cannot be reached, c is populated from the
+// last-known-good values and a *StaleError is returned.
+//
+// If p.Retry is set, requests that fail with throttling or transient errors are retried.
+// If they still fail the error wraps a *RetryError reporting the number of attempts.
 func (p *Provider) Process(configPath string, c interface{}) error {
+	return p.ProcessWithContext(context.Background(), configPath, c)
+}
+
+// ProcessWithContext is the same as Process with the addition of a context that is used
+// for requests to Parameter Store.
+func (p *Provider) ProcessWithContext(ctx context.Context, configPath string, c interface{}) error {
+	_, err := p.ProcessWithReport(ctx, configPath, c)
+	return err
+}
+
+// ProcessWithReport is the same as ProcessWithContext, and also returns a Report
+// describing where the value of each field came from.
+//
+// The report is returned whenever parameters were fetched, including when an error is
+// returned, in which case it describes the fields resolved before the error.
+func (p *Provider) ProcessWithReport(ctx context.Context, configPath string, c interface{}) (*Report, error) {
+	o := p.observer()
+	start := time.Now()
+	ctx = o.ProcessStart(ctx, configPath)
+
+	report, err := p.process(ctx, configPath, c)
+	o.ProcessEnd(ctx, ProcessInfo{ConfigPath: configPath, Duration: time.Since(start), Err: err})
+	return report, err
+}
+
+func (p *Provider) process(ctx context.Context, configPath string, c interface{}) (*Report, error) {
+	spec, t, err := specFor(configPath, c)
+	if err != nil {
+		return nil, err
+	}
 
+	decrypted, plain := p.requestNames(spec, nil)
+	res, err := p.getParameters(ctx, decrypted, plain)
+	stale, _ := err.(*StaleError)
+	if err != nil && stale == nil {
+		return nil, errors.Wrap(err, "ssmconfig: could not get parameters")
+	}
+
+	if stale == nil {
+		if err := p.checkKeyIDs(ctx, spec, res.params, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	report := newReport(configPath, res, stale)
+	for i := range spec {
+		info, err := spec.setField(ctx, p, t, i, res.params, res.invalid)
+		report.addField(info, res.params[info.Parameter])
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if stale != nil {
+		return report, stale
+	}
+	return report, nil
+}
+
+// specFor returns the spec of c resolved against configPath and the target used to
+// populate it. Generated code is used when c implements Generated.
+func specFor(configPath string, c interface{}) (structSpec, target, error) {
+	if g, ok := c.(Generated); ok {
+		if v := reflect.ValueOf(c); v.Kind() == reflect.Ptr && !v.IsNil() {
+			return buildGeneratedSpec(configPath, g), generatedTarget{g}, nil
+		}
+	}
+
+	v, err := structValue(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	return buildStructSpec(configPath, v.Type()), structTarget{v}, nil
+}
+
+// structValue returns the struct c points to.
+func structValue(c interface{}) (reflect.Value, error) {
 	v := reflect.ValueOf(c)
 	if v.Kind() != reflect.Ptr || v.IsNil() {
-		return errors.New("ssmconfig: c must be a pointer to a struct")
+		return reflect.Value{}, errors.New("ssmconfig: c must be a pointer to a struct")
 	}
 
-	v = reflect.Indirect(reflect.ValueOf(c))
+	v = reflect.Indirect(v)
 	if v.Kind() != reflect.Struct {
-		return errors.New("ssmconfig: c must be a pointer to a struct")
+		return reflect.Value{}, errors.New("ssmconfig: c must be a pointer to a struct")
 	}
+	return v, nil
+}
 
-	spec := buildStructSpec(configPath, v.Type())
+// fetchResult is the result of fetching parameters.
+type fetchResult struct {
+	params  map[string]*ssm.Parameter
+	invalid map[string]struct{}
 
-	params, invalidPrams, err := p.getParameters(spec)
-	if err != nil {
-		return errors.Wrap(err, "ssmconfig: could not get parameters")
-	}
+	cached   int  // number of parameters served from the cache
+	requests int  // number of requests made, including retries
+	retries  int  // number of requests that were retries
+	shared   bool // whether the result of an in-flight fetch was shared
+}
 
-	for i, field := range spec {
-		if field.name == "" {
-			continue
+// maxGetParametersNames is the maximum number of names GetParameters accepts in a single
+// request.
+const maxGetParametersNames = 10
+
+// getParameters fetches the decrypted names with decryption and the plain names without.
+func (p *Provider) getParameters(ctx context.Context, decrypted, plain []string) (res fetchResult, err error) {
+	names := append(append([]string(nil), decrypted...), plain...)
+
+	o := p.observer()
+	start := time.Now()
+	ctx = o.FetchStart(ctx, names)
+	defer func() {
+		info := FetchInfo{
+			Names:    names,
+			Cached:   res.cached,
+			Requests: res.requests,
+			Retries:  res.retries,
+			Shared:   res.shared,
+			Duration: time.Since(start),
+			Err:      err,
 		}
-
-		if _, ok := invalidPrams[field.name]; ok && field.required {
-			return errors.Errorf("ssmconfig: %s is required", field.name)
+		for name := range res.invalid {
+			info.Invalid = append(info.Invalid, name)
 		}
+		sort.Strings(info.Invalid)
+		o.FetchEnd(ctx, info)
+	}()
 
-		value, ok := params[field.name]
-		if !ok {
-			value = field.defaultValue
-		}
+	res = fetchResult{
+		params:  map[string]*ssm.Parameter{},
+		invalid: map[string]struct{}{},
+	}
 
-		if value == "" {
+	var stale *StaleError
+	for _, group := range []struct {
+		names   []string
+		decrypt bool
+	}{{decrypted, true}, {plain, false}} {
+		if len(group.names) == 0 {
 			continue
 		}
 
-		err = setValue(v.Field(i), value)
+		err := p.getGroup(ctx, group.names, group.decrypt, &res)
+		if err, ok := err.(*StaleError); ok {
+			// The oldest values determine how stale the result is.
+			if stale == nil || err.SavedAt.Before(stale.SavedAt) {
+				stale = err
+			}
+			continue
+		}
 		if err != nil {
-			return errors.Wrapf(err, "ssmconfig: error setting field %s", v.Type().Field(i).Name)
+			return res, err
 		}
 	}
 
-	return nil
+	if stale != nil {
+		return res, stale
+	}
+	return res, nil
 }
 
-func (p *Provider) getParameters(spec structSpec) (params map[string]string, invalidParams map[string]struct{}, err error) {
-	// find all of the params that need to be requested
-	var names []*string
-	for i := range spec {
-		if spec[i].name == "" {
-			continue
+// getGroup fetches names with the given decryption setting and adds them to res.
+func (p *Provider) getGroup(ctx context.Context, names []string, decrypt bool, res *fetchResult) error {
+	missing := names
+	if p.Cache != nil {
+		missing = p.Cache.lookup(names, decrypt, res.params, res.invalid)
+		res.cached += len(names) - len(missing)
+		if len(missing) == 0 {
+			return nil
 		}
-		names = append(names, &spec[i].name)
 	}
 
-	input := &ssm.GetParametersInput{
-		Names:          names,
-		WithDecryption: aws.Bool(true),
+	// Concurrent fetches of the same uncached names share a single set of requests.
+	fetched, shared, err := p.flights.do(ctx, missing, decrypt, func() (fetchResult, error) {
+		return p.fetch(ctx, missing, decrypt)
+	})
+	for name, param := range fetched.params {
+		res.params[name] = param
 	}
+	for name := range fetched.invalid {
+		res.invalid[name] = struct{}{}
+	}
+	res.cached += fetched.cached
+	res.requests += fetched.requests
+	res.retries += fetched.retries
+	res.shared = res.shared || shared
+	return err
+}
 
-	output, err := p.SSM.GetParameters(input)
-	if err != nil {
-		return nil, nil, err
+// fetch requests names from Parameter Store in batches, with the given decryption setting.
+// Successfully fetched parameters are stored in the cache and the last-known-good values.
+func (p *Provider) fetch(ctx context.Context, names []string, decrypt bool) (fetchResult, error) {
+	res := fetchResult{
+		params:  map[string]*ssm.Parameter{},
+		invalid: map[string]struct{}{},
 	}
-	if output == nil {
-		return nil, nil, nil
+
+	// The cache is checked again in case an identical fetch completed after the caller's
+	// lookup, so that a burst of calls results in a single request.
+	if p.Cache != nil {
+		n := len(names)
+		names = p.Cache.lookup(names, decrypt, res.params, res.invalid)
+		res.cached = n - len(names)
 	}
 
-	// convert the response to a map for easier use later
-	params = map[string]string{}
-	for i := range output.Parameters {
-		params[*output.Parameters[i].Name] = *output.Parameters[i].Value
+	for i := 0; i < len(names); i += maxGetParametersNames {
+		batch := names[i:]
+		if len(batch) > maxGetParametersNames {
+			batch = batch[:maxGetParametersNames]
+		}
+
+		output, retries, err := p.observeBatch(ctx, batch, decrypt)
+		res.requests += 1 + retries
+		res.retries += retries
+		if err != nil {
+			if p.LastKnownGood != nil && canFallback(ctx, err) {
+				res.params, res.invalid, err = p.LastKnownGood.fallback(names[i:], decrypt, res.params, res.invalid, err)
+			}
+			return res, err
+		}
+		if output == nil {
+			continue
+		}
+
+		// convert the response to a map for easier use later
+		for i := range output.Parameters {
+			res.params[*output.Parameters[i].Name] = output.Parameters[i]
+		}
+
+		for i := range output.InvalidParameters {
+			res.invalid[*output.InvalidParameters[i]] = struct{}{}
+		}
+
+		if p.Cache != nil {
+			p.Cache.store(output.Parameters, output.InvalidParameters, decrypt)
+		}
+		if p.LastKnownGood != nil {
+			p.LastKnownGood.save(output.Parameters, output.InvalidParameters, decrypt)
+		}
 	}
+	return res, nil
+}
 
-	invalidParams = map[string]struct{}{}
-	for i := range output.InvalidParameters {
-		invalidParams[*output.InvalidParameters[i]] = struct{}{}
+// observeBatch calls getParametersBatch, reporting the request to the observer.
+func (p *Provider) observeBatch(ctx context.Context, names []string, decrypt bool) (*ssm.GetParametersOutput, int, error) {
+	o := p.observer()
+	start := time.Now()
+	ctx = o.BatchStart(ctx, names)
+
+	output, retries, err := p.getParametersBatch(ctx, names, decrypt)
+
+	info := BatchInfo{Names: names, Retries: retries, Duration: time.Since(start), Err: err}
+	if output != nil {
+		info.Invalid = len(output.InvalidParameters)
 	}
-	return params, invalidParams, nil
+	o.BatchEnd(ctx, info)
+
+	if err != nil && p.Logger != nil {
+		p.Logger.LogAttrs(ctx, slog.LevelDebug, "ssmconfig: GetParameters request failed",
+			slog.Int("parameters", len(names)),
+			slog.Bool("decrypt", decrypt),
+			slog.Int("retries", retries),
+			slog.Any("error", err),
+		)
+	}
+	return output, retries, err
 }
 
-func setValue(v reflect.Value, s string) error {
-	switch v.Kind() {
-	case reflect.String:
-		v.SetString(s)
+// getParametersBatch requests names from Parameter Store with the given decryption
+// setting, applying the rate limiter and retry policy.
+func (p *Provider) getParametersBatch(ctx context.Context, names []string, decrypt bool) (output *ssm.GetParametersOutput, retries int, err error) {
+	retries, err = p.Retry.do(ctx, func() error {
+		if err := p.wait(ctx); err != nil {
+			return err
+		}
 
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		i, err := strconv.Atoi(s)
-		if err != nil {
-			return errors.Errorf("could not decode %q into type %v", s, v.Type().String())
+		input := &ssm.GetParametersInput{
+			Names:          aws.StringSlice(names),
+			WithDecryption: aws.Bool(decrypt),
 		}
-		v.SetInt(int64(i))
 
-	case reflect.Float32:
-		f, err := strconv.ParseFloat(s, 32)
-		if err != nil {
-			return errors.Errorf("could not decode %q into type %v: %v", s, v.Type().String(), err)
+		// GetParameters is used when ctx cannot be canceled, so that clients which only
+		// implement GetParameters, such as mocks written for earlier versions, still work
+		// with Process.
+		var err error
+		if ctx.Done() == nil {
+			output, err = p.SSM.GetParameters(input)
+		} else {
+			output, err = p.SSM.GetParametersWithContext(ctx, input)
 		}
-		v.SetFloat(f)
+		return err
+	})
+	return output, retries, err
+}
 
-	case reflect.Float64:
-		f, err := strconv.ParseFloat(s, 64)
-		if err != nil {
-			return errors.Errorf("could not decode %q into type %v: %v", s, v.Type().String(), err)
+// wait blocks until the rate limiter allows a request to be made.
+func (p *Provider) wait(ctx context.Context) error {
+	if p.RateLimiter == nil {
+		return nil
+	}
+	return p.RateLimiter.Wait(ctx)
+}
+
+// maxDescribeFilterValues is the maximum number of values DescribeParameters accepts in a
+// single filter.
+const maxDescribeFilterValues = 50
+
+// describeParameters returns the metadata of the named parameters that exist, keyed by
+// name.
+func (p *Provider) describeParameters(ctx context.Context, names []string) (map[string]*ssm.ParameterMetadata, error) {
+	metadata := map[string]*ssm.ParameterMetadata{}
+	for len(names) > 0 {
+		n := len(names)
+		if n > maxDescribeFilterValues {
+			n = maxDescribeFilterValues
 		}
-		v.SetFloat(f)
 
-	case reflect.Bool:
-		if s != "true" && s != "false" {
-			return errors.Errorf("could not decode %q into type %v", s, v.Type().String())
+		input := &ssm.DescribeParametersInput{
+			ParameterFilters: []*ssm.ParameterStringFilter{{
+				Key:    aws.String("Name"),
+				Option: aws.String("Equals"),
+				Values: aws.StringSlice(names[:n]),
+			}},
+			MaxResults: aws.Int64(50),
+		}
+		for {
+			var output *ssm.DescribeParametersOutput
+			_, err := p.Retry.do(ctx, func() error {
+				if err := p.wait(ctx); err != nil {
+					return err
+				}
+
+				var err error
+				output, err = p.SSM.DescribeParametersWithContext(ctx, input)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, m := range output.Parameters {
+				metadata[aws.StringValue(m.Name)] = m
+			}
+			if aws.StringValue(output.NextToken) == "" {
+				break
+			}
+			input.NextToken = output.NextToken
 		}
-		v.SetBool(s == "true")
 
+		names = names[n:]
+	}
+	return metadata, nil
+}
+
+func setValue(v reflect.Value, s string) error {
+	return decoderFor(v.Type())(v, s)
+}
+
+// decodeFunc decodes s into v.
+type decodeFunc func(v reflect.Value, s string) error
+
+// decoderFor returns the decodeFunc for values of type t.
+func decoderFor(t reflect.Type) decodeFunc {
+	if reflect.PointerTo(t).Implements(secretDecoderType) {
+		return decodeSecret
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return decodeString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decodeInt
+	case reflect.Float32:
+		return decodeFloat32
+	case reflect.Float64:
+		return decodeFloat64
+	case reflect.Bool:
+		return decodeBool
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return decodeBytes
+		}
+		return decodeUnsupported
 	default:
-		return errors.Errorf("could not decode %q into type %v", s, v.Type().String())
+		return decodeUnsupported
 	}
+}
 
+func decodeString(v reflect.Value, s string) error {
+	v.SetString(s)
 	return nil
 }
 
-type structSpec []fieldSpec
+func decodeInt(v reflect.Value, s string) error {
+	i, err := DecodeInt(s, v.Type().String())
+	if err != nil {
+		return err
+	}
+	v.SetInt(i)
+	return nil
+}
 
-type fieldSpec struct {
-	name         string
-	defaultValue string
-	required     bool
+func decodeFloat32(v reflect.Value, s string) error {
+	f, err := DecodeFloat(s, 32, v.Type().String())
+	if err != nil {
+		return err
+	}
+	v.SetFloat(f)
+	return nil
 }
 
-func buildStructSpec(configPath string, t reflect.Type) (spec structSpec) {
-	for i := 0; i < t.NumField(); i++ {
-		name := t.Field(i).Tag.Get("ssm")
-		if name != "" {
-			name = path.Join(configPath, name)
-		}
+func decodeFloat64(v reflect.Value, s string) error {
+	f, err := DecodeFloat(s, 64, v.Type().String())
+	if err != nil {
+		return err
+	}
+	v.SetFloat(f)
+	return nil
+}
 
-		spec = append(spec, fieldSpec{
-			name:         name,
-			defaultValue: t.Field(i).Tag.Get("default"),
-			required:     t.Field(i).Tag.Get("required") == "true",
-		})
+func decodeBool(v reflect.Value, s string) error {
+	b, err := DecodeBool(s, v.Type().String())
+	if err != nil {
+		return err
 	}
-	return spec
+	v.SetBool(b)
+	return nil
+}
+
+func decodeBytes(v reflect.Value, s string) error {
+	v.SetBytes([]byte(s))
+	return nil
+}
+
+func decodeUnsupported(v reflect.Value, s string) error {
+	return DecodeUnsupported(s, v.Type().String())
 }
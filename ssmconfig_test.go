@@ -284,3 +284,36 @@ func TestProvider_Process(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkProvider_Process(b *testing.B) {
+	type config struct {
+		S1    string  `ssm:"/strings/s1"`
+		S2    string  `ssm:"/strings/s2" default:"string2"`
+		I1    int     `ssm:"/int/i1"`
+		I2    int     `ssm:"/int/i2" default:"42"`
+		B1    bool    `ssm:"/bool/b1"`
+		F641  float64 `ssm:"/float64/f641" required:"true"`
+		NoSSM string
+	}
+
+	mc := &mockSSMClient{
+		output: &ssm.GetParametersOutput{
+			Parameters: []*ssm.Parameter{
+				{Name: aws.String("/base/strings/s1"), Value: aws.String("string1")},
+				{Name: aws.String("/base/int/i1"), Value: aws.String("42")},
+				{Name: aws.String("/base/bool/b1"), Value: aws.String("true")},
+				{Name: aws.String("/base/float64/f641"), Value: aws.String("42.42")},
+			},
+			InvalidParameters: aws.StringSlice([]string{"/base/strings/s2", "/base/int/i2"}),
+		},
+	}
+	p := &ssmconfig.Provider{SSM: mc}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var c config
+		if err := p.Process("/base/", &c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
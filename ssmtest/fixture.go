@@ -0,0 +1,561 @@
+package ssmtest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/ianlopshire/go-ssm-config/internal/seal"
+	"github.com/pkg/errors"
+)
+
+// RedactedValue replaces the value of SecureString parameters in fixtures recorded
+// without a key.
+const RedactedValue = "REDACTED"
+
+// sealedPrefix marks SecureString values in fixtures that are encrypted with a key.
+const sealedPrefix = "sealed:"
+
+// Fixture is a recorded sequence of SSM requests and responses.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded SSM request and its response.
+type Interaction struct {
+	Operation string          `json:"operation"`
+	Input     json.RawMessage `json:"input"`
+	Output    json.RawMessage `json:"output,omitempty"`
+	Error     *FixtureError   `json:"error,omitempty"`
+}
+
+// FixtureError is a recorded error response.
+type FixtureError struct {
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode,omitempty"`
+}
+
+func newFixtureError(err error) *FixtureError {
+	if err == nil {
+		return nil
+	}
+
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return &FixtureError{Message: err.Error()}
+	}
+
+	ferr := &FixtureError{Code: aerr.Code(), Message: aerr.Message()}
+	if rerr, ok := err.(awserr.RequestFailure); ok {
+		ferr.StatusCode = rerr.StatusCode()
+	}
+	return ferr
+}
+
+func (e *FixtureError) err() error {
+	if e.Code == "" {
+		return errors.New(e.Message)
+	}
+
+	err := awserr.New(e.Code, e.Message, nil)
+	if e.StatusCode != 0 {
+		return awserr.NewRequestFailure(err, e.StatusCode, "")
+	}
+	return err
+}
+
+// ReadFixture reads a fixture from a file written by Recorder.Save.
+func ReadFixture(filename string) (*Fixture, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "ssmtest: could not read fixture")
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, errors.Wrap(err, "ssmtest: could not decode fixture")
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to a file.
+func (f *Fixture) Save(filename string) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "ssmtest: could not encode fixture")
+	}
+
+	err = ioutil.WriteFile(filename, append(b, '\n'), 0600)
+	return errors.Wrap(err, "ssmtest: could not write fixture")
+}
+
+// Recorder is an SSM client that records the requests made to the wrapped client so they
+// can be replayed by a Replayer.
+//
+// GetParameter, GetParameters, GetParametersByPath, and DescribeParameters are recorded.
+// Other methods are passed directly to the wrapped client.
+//
+// SecureString values are never written in plaintext. If Key is set they are encrypted
+// with AES-GCM, otherwise they are replaced with RedactedValue.
+type Recorder struct {
+	ssmiface.SSMAPI
+
+	// Key is used to encrypt SecureString values. It must be 16, 24, or 32 bytes long.
+	Key []byte
+
+	mu      sync.Mutex
+	fixture Fixture
+	err     error
+}
+
+// NewRecorder returns a Recorder that wraps client.
+func NewRecorder(client ssmiface.SSMAPI, key []byte) *Recorder {
+	return &Recorder{SSMAPI: client, Key: key}
+}
+
+// Fixture returns the interactions recorded so far.
+func (r *Recorder) Fixture() (*Fixture, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	f := &Fixture{Interactions: make([]Interaction, len(r.fixture.Interactions))}
+	copy(f.Interactions, r.fixture.Interactions)
+	return f, nil
+}
+
+// Save writes the interactions recorded so far to a file.
+func (r *Recorder) Save(filename string) error {
+	f, err := r.Fixture()
+	if err != nil {
+		return err
+	}
+	return f.Save(filename)
+}
+
+func (r *Recorder) record(op string, input, output interface{}, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+
+	i := Interaction{Operation: op, Error: newFixtureError(err)}
+	i.Input, r.err = json.Marshal(input)
+	if r.err != nil {
+		r.err = errors.Wrapf(r.err, "ssmtest: could not encode %s input", op)
+		return
+	}
+
+	if err == nil {
+		r.err = redactOutput(output, r.Key)
+		if r.err != nil {
+			return
+		}
+		i.Output, r.err = json.Marshal(output)
+		if r.err != nil {
+			r.err = errors.Wrapf(r.err, "ssmtest: could not encode %s output", op)
+			return
+		}
+	}
+
+	r.fixture.Interactions = append(r.fixture.Interactions, i)
+}
+
+// GetParameter calls the wrapped client and records the interaction.
+func (r *Recorder) GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	return r.GetParameterWithContext(aws.BackgroundContext(), input)
+}
+
+// GetParameterWithContext calls the wrapped client and records the interaction.
+func (r *Recorder) GetParameterWithContext(ctx aws.Context, input *ssm.GetParameterInput, opts ...request.Option) (*ssm.GetParameterOutput, error) {
+	output, err := r.SSMAPI.GetParameterWithContext(ctx, input, opts...)
+	r.record("GetParameter", input, copyOutput(output), err)
+	return output, err
+}
+
+// GetParameters calls the wrapped client and records the interaction.
+func (r *Recorder) GetParameters(input *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+	return r.GetParametersWithContext(aws.BackgroundContext(), input)
+}
+
+// GetParametersWithContext calls the wrapped client and records the interaction.
+func (r *Recorder) GetParametersWithContext(ctx aws.Context, input *ssm.GetParametersInput, opts ...request.Option) (*ssm.GetParametersOutput, error) {
+	output, err := r.SSMAPI.GetParametersWithContext(ctx, input, opts...)
+	r.record("GetParameters", input, copyOutput(output), err)
+	return output, err
+}
+
+// GetParametersByPath calls the wrapped client and records the interaction.
+func (r *Recorder) GetParametersByPath(input *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+	return r.GetParametersByPathWithContext(aws.BackgroundContext(), input)
+}
+
+// GetParametersByPathWithContext calls the wrapped client and records the interaction.
+func (r *Recorder) GetParametersByPathWithContext(ctx aws.Context, input *ssm.GetParametersByPathInput, opts ...request.Option) (*ssm.GetParametersByPathOutput, error) {
+	output, err := r.SSMAPI.GetParametersByPathWithContext(ctx, input, opts...)
+	r.record("GetParametersByPath", input, copyOutput(output), err)
+	return output, err
+}
+
+// GetParametersByPathPages calls the wrapped client and records each page.
+func (r *Recorder) GetParametersByPathPages(input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool) error {
+	return r.GetParametersByPathPagesWithContext(aws.BackgroundContext(), input, fn)
+}
+
+// GetParametersByPathPagesWithContext calls the wrapped client and records each page.
+func (r *Recorder) GetParametersByPathPagesWithContext(ctx aws.Context, input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool, opts ...request.Option) error {
+	return getParametersByPathPages(ctx, r.GetParametersByPathWithContext, input, fn, opts...)
+}
+
+// DescribeParameters calls the wrapped client and records the interaction.
+func (r *Recorder) DescribeParameters(input *ssm.DescribeParametersInput) (*ssm.DescribeParametersOutput, error) {
+	return r.DescribeParametersWithContext(aws.BackgroundContext(), input)
+}
+
+// DescribeParametersWithContext calls the wrapped client and records the interaction.
+func (r *Recorder) DescribeParametersWithContext(ctx aws.Context, input *ssm.DescribeParametersInput, opts ...request.Option) (*ssm.DescribeParametersOutput, error) {
+	output, err := r.SSMAPI.DescribeParametersWithContext(ctx, input, opts...)
+	r.record("DescribeParameters", input, output, err)
+	return output, err
+}
+
+// DescribeParametersPages calls the wrapped client and records each page.
+func (r *Recorder) DescribeParametersPages(input *ssm.DescribeParametersInput, fn func(*ssm.DescribeParametersOutput, bool) bool) error {
+	return r.DescribeParametersPagesWithContext(aws.BackgroundContext(), input, fn)
+}
+
+// DescribeParametersPagesWithContext calls the wrapped client and records each page.
+func (r *Recorder) DescribeParametersPagesWithContext(ctx aws.Context, input *ssm.DescribeParametersInput, fn func(*ssm.DescribeParametersOutput, bool) bool, opts ...request.Option) error {
+	return describeParametersPages(ctx, r.DescribeParametersWithContext, input, fn, opts...)
+}
+
+// Replayer is an SSM client that serves the responses recorded in a Fixture. A request
+// that does not match an unused recorded interaction results in an error.
+//
+// Only the methods recorded by Recorder are supported. Calling any other method will
+// panic.
+type Replayer struct {
+	ssmiface.SSMAPI
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a Replayer that serves the interactions in f. key is used to decrypt
+// SecureString values that were encrypted when the fixture was recorded.
+func NewReplayer(f *Fixture, key []byte) (*Replayer, error) {
+	r := &Replayer{
+		interactions: make([]Interaction, len(f.Interactions)),
+		used:         make([]bool, len(f.Interactions)),
+	}
+
+	for i, interaction := range f.Interactions {
+		if interaction.Output != nil {
+			output, err := decodeOutput(interaction.Operation, interaction.Output)
+			if err != nil {
+				return nil, err
+			}
+			if err := unsealOutput(output, key); err != nil {
+				return nil, err
+			}
+			if interaction.Output, err = json.Marshal(output); err != nil {
+				return nil, errors.Wrap(err, "ssmtest: could not encode output")
+			}
+		}
+		r.interactions[i] = interaction
+	}
+
+	return r, nil
+}
+
+// LoadReplayer reads a fixture from a file and returns a Replayer that serves it.
+func LoadReplayer(filename string, key []byte) (*Replayer, error) {
+	f, err := ReadFixture(filename)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(f, key)
+}
+
+// Unused returns the recorded interactions that have not been replayed.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, r.interactions[i])
+		}
+	}
+	return unused
+}
+
+// replay finds the first unused interaction matching op and input and decodes its output
+// into output.
+func (r *Replayer) replay(op string, input, output interface{}) error {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return errors.Wrapf(err, "ssmtest: could not encode %s input", op)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Operation != op || !jsonEqual(interaction.Input, b) {
+			continue
+		}
+		r.used[i] = true
+
+		if interaction.Error != nil {
+			return interaction.Error.err()
+		}
+		return errors.Wrapf(json.Unmarshal(interaction.Output, output), "ssmtest: could not decode %s output", op)
+	}
+
+	return errors.Errorf("ssmtest: unexpected %s request: %s", op, b)
+}
+
+// GetParameter serves a recorded GetParameter response.
+func (r *Replayer) GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	return r.GetParameterWithContext(aws.BackgroundContext(), input)
+}
+
+// GetParameterWithContext serves a recorded GetParameter response.
+func (r *Replayer) GetParameterWithContext(_ aws.Context, input *ssm.GetParameterInput, _ ...request.Option) (*ssm.GetParameterOutput, error) {
+	output := &ssm.GetParameterOutput{}
+	if err := r.replay("GetParameter", input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// GetParameters serves a recorded GetParameters response.
+func (r *Replayer) GetParameters(input *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+	return r.GetParametersWithContext(aws.BackgroundContext(), input)
+}
+
+// GetParametersWithContext serves a recorded GetParameters response.
+func (r *Replayer) GetParametersWithContext(_ aws.Context, input *ssm.GetParametersInput, _ ...request.Option) (*ssm.GetParametersOutput, error) {
+	output := &ssm.GetParametersOutput{}
+	if err := r.replay("GetParameters", input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// GetParametersByPath serves a recorded GetParametersByPath response.
+func (r *Replayer) GetParametersByPath(input *ssm.GetParametersByPathInput) (*ssm.GetParametersByPathOutput, error) {
+	return r.GetParametersByPathWithContext(aws.BackgroundContext(), input)
+}
+
+// GetParametersByPathWithContext serves a recorded GetParametersByPath response.
+func (r *Replayer) GetParametersByPathWithContext(_ aws.Context, input *ssm.GetParametersByPathInput, _ ...request.Option) (*ssm.GetParametersByPathOutput, error) {
+	output := &ssm.GetParametersByPathOutput{}
+	if err := r.replay("GetParametersByPath", input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// GetParametersByPathPages serves recorded GetParametersByPath responses.
+func (r *Replayer) GetParametersByPathPages(input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool) error {
+	return r.GetParametersByPathPagesWithContext(aws.BackgroundContext(), input, fn)
+}
+
+// GetParametersByPathPagesWithContext serves recorded GetParametersByPath responses.
+func (r *Replayer) GetParametersByPathPagesWithContext(ctx aws.Context, input *ssm.GetParametersByPathInput, fn func(*ssm.GetParametersByPathOutput, bool) bool, opts ...request.Option) error {
+	return getParametersByPathPages(ctx, r.GetParametersByPathWithContext, input, fn, opts...)
+}
+
+// DescribeParameters serves a recorded DescribeParameters response.
+func (r *Replayer) DescribeParameters(input *ssm.DescribeParametersInput) (*ssm.DescribeParametersOutput, error) {
+	return r.DescribeParametersWithContext(aws.BackgroundContext(), input)
+}
+
+// DescribeParametersWithContext serves a recorded DescribeParameters response.
+func (r *Replayer) DescribeParametersWithContext(_ aws.Context, input *ssm.DescribeParametersInput, _ ...request.Option) (*ssm.DescribeParametersOutput, error) {
+	output := &ssm.DescribeParametersOutput{}
+	if err := r.replay("DescribeParameters", input, output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// DescribeParametersPages serves recorded DescribeParameters responses.
+func (r *Replayer) DescribeParametersPages(input *ssm.DescribeParametersInput, fn func(*ssm.DescribeParametersOutput, bool) bool) error {
+	return r.DescribeParametersPagesWithContext(aws.BackgroundContext(), input, fn)
+}
+
+// DescribeParametersPagesWithContext serves recorded DescribeParameters responses.
+func (r *Replayer) DescribeParametersPagesWithContext(ctx aws.Context, input *ssm.DescribeParametersInput, fn func(*ssm.DescribeParametersOutput, bool) bool, opts ...request.Option) error {
+	return describeParametersPages(ctx, r.DescribeParametersWithContext, input, fn, opts...)
+}
+
+// getParametersByPathPages iterates over the pages of a GetParametersByPath operation
+// using get to request each page. Pagination is implemented here, rather than by the SDK,
+// so that each page passes through the Recorder or Replayer.
+func getParametersByPathPages(
+	ctx aws.Context,
+	get func(aws.Context, *ssm.GetParametersByPathInput, ...request.Option) (*ssm.GetParametersByPathOutput, error),
+	input *ssm.GetParametersByPathInput,
+	fn func(*ssm.GetParametersByPathOutput, bool) bool,
+	opts ...request.Option,
+) error {
+	in := *input
+	for {
+		output, err := get(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(output.NextToken) == ""
+		if !fn(output, last) || last {
+			return nil
+		}
+		in.NextToken = output.NextToken
+	}
+}
+
+// describeParametersPages is the DescribeParameters equivalent of
+// getParametersByPathPages.
+func describeParametersPages(
+	ctx aws.Context,
+	describe func(aws.Context, *ssm.DescribeParametersInput, ...request.Option) (*ssm.DescribeParametersOutput, error),
+	input *ssm.DescribeParametersInput,
+	fn func(*ssm.DescribeParametersOutput, bool) bool,
+	opts ...request.Option,
+) error {
+	in := *input
+	for {
+		output, err := describe(ctx, &in, opts...)
+		if err != nil {
+			return err
+		}
+		last := aws.StringValue(output.NextToken) == ""
+		if !fn(output, last) || last {
+			return nil
+		}
+		in.NextToken = output.NextToken
+	}
+}
+
+// copyOutput returns a copy of output that can be redacted without modifying the value
+// returned to the caller.
+func copyOutput(output interface{}) interface{} {
+	v := reflect.ValueOf(output)
+	if !v.IsValid() || v.IsNil() {
+		return output
+	}
+
+	b, err := json.Marshal(output)
+	if err != nil {
+		return output
+	}
+	c := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal(b, c.Interface()); err != nil {
+		return output
+	}
+	return c.Interface()
+}
+
+func decodeOutput(op string, b json.RawMessage) (interface{}, error) {
+	var output interface{}
+	switch op {
+	case "GetParameter":
+		output = &ssm.GetParameterOutput{}
+	case "GetParameters":
+		output = &ssm.GetParametersOutput{}
+	case "GetParametersByPath":
+		output = &ssm.GetParametersByPathOutput{}
+	case "DescribeParameters":
+		output = &ssm.DescribeParametersOutput{}
+	default:
+		return nil, errors.Errorf("ssmtest: unsupported operation %q", op)
+	}
+
+	err := json.Unmarshal(b, output)
+	return output, errors.Wrapf(err, "ssmtest: could not decode %s output", op)
+}
+
+// outputParameters returns the parameters contained in an output.
+func outputParameters(output interface{}) []*ssm.Parameter {
+	switch o := output.(type) {
+	case *ssm.GetParameterOutput:
+		if o != nil && o.Parameter != nil {
+			return []*ssm.Parameter{o.Parameter}
+		}
+	case *ssm.GetParametersOutput:
+		if o != nil {
+			return o.Parameters
+		}
+	case *ssm.GetParametersByPathOutput:
+		if o != nil {
+			return o.Parameters
+		}
+	}
+	return nil
+}
+
+// redactOutput encrypts or masks the values of the SecureString parameters in output.
+func redactOutput(output interface{}, key []byte) error {
+	for _, p := range outputParameters(output) {
+		if aws.StringValue(p.Type) != ssm.ParameterTypeSecureString || p.Value == nil {
+			continue
+		}
+
+		if key == nil {
+			p.Value = aws.String(RedactedValue)
+			continue
+		}
+
+		sealed, err := seal.Seal(key, *p.Value)
+		if err != nil {
+			return errors.Wrapf(err, "ssmtest: could not encrypt %s", aws.StringValue(p.Name))
+		}
+		p.Value = aws.String(sealedPrefix + sealed)
+	}
+	return nil
+}
+
+// unsealOutput decrypts the SecureString values in output that were encrypted by
+// redactOutput.
+func unsealOutput(output interface{}, key []byte) error {
+	for _, p := range outputParameters(output) {
+		if p.Value == nil || !strings.HasPrefix(*p.Value, sealedPrefix) {
+			continue
+		}
+
+		if key == nil {
+			return errors.Errorf("ssmtest: a key is required to decrypt %s", aws.StringValue(p.Name))
+		}
+
+		value, err := seal.Open(key, strings.TrimPrefix(*p.Value, sealedPrefix))
+		if err != nil {
+			return errors.Wrapf(err, "ssmtest: could not decrypt %s", aws.StringValue(p.Name))
+		}
+		p.Value = aws.String(value)
+	}
+	return nil
+}
+
+// jsonEqual reports whether a and b encode equal JSON values.
+func jsonEqual(a, b []byte) bool {
+	var av, bv interface{}
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
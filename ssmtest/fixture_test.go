@@ -0,0 +1,126 @@
+package ssmtest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+type fixtureConfig struct {
+	Port   int    `ssm:"port"`
+	User   string `ssm:"user" default:"guest"`
+	Secret string `ssm:"secret" required:"true"`
+}
+
+func recordFixture(t *testing.T, key []byte) string {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/service/prod/port", "8080")
+	s.PutSecure("/service/prod/secret", "hunter2")
+
+	rec := ssmtest.NewRecorder(s.Client(), key)
+	p := &ssmconfig.Provider{SSM: rec}
+
+	var c fixtureConfig
+	if err := p.Process("/service/prod", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	if c.Secret != "hunter2" {
+		t.Fatalf("Process() recorded client returned redacted value %q", c.Secret)
+	}
+
+	dir, err := ioutil.TempDir("", "ssmtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "fixture.json")
+	if err := rec.Save(filename); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "hunter2") {
+		t.Fatalf("Save() fixture contains SecureString value:\n%s", b)
+	}
+
+	return filename
+}
+
+func TestRecorder_Replayer(t *testing.T) {
+	t.Run("encrypted", func(t *testing.T) {
+		key := []byte("0123456789abcdef")
+		filename := recordFixture(t, key)
+		defer os.RemoveAll(filepath.Dir(filename))
+
+		rp, err := ssmtest.LoadReplayer(filename, key)
+		if err != nil {
+			t.Fatalf("LoadReplayer() unexpected error: %v", err)
+		}
+
+		var c fixtureConfig
+		if err := (&ssmconfig.Provider{SSM: rp}).Process("/service/prod", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+
+		want := fixtureConfig{Port: 8080, User: "guest", Secret: "hunter2"}
+		if c != want {
+			t.Errorf("Process() want %+v, have %+v", want, c)
+		}
+		if unused := rp.Unused(); len(unused) != 0 {
+			t.Errorf("Unused() want no interactions, have %d", len(unused))
+		}
+
+		if _, err := ssmtest.LoadReplayer(filename, nil); err == nil {
+			t.Errorf("LoadReplayer() expected error without key")
+		}
+	})
+
+	t.Run("masked", func(t *testing.T) {
+		filename := recordFixture(t, nil)
+		defer os.RemoveAll(filepath.Dir(filename))
+
+		rp, err := ssmtest.LoadReplayer(filename, nil)
+		if err != nil {
+			t.Fatalf("LoadReplayer() unexpected error: %v", err)
+		}
+
+		var c fixtureConfig
+		if err := (&ssmconfig.Provider{SSM: rp}).Process("/service/prod", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if c.Secret != ssmtest.RedactedValue {
+			t.Errorf("Process() Secret want %q, have %q", ssmtest.RedactedValue, c.Secret)
+		}
+	})
+
+	t.Run("unexpected request", func(t *testing.T) {
+		filename := recordFixture(t, nil)
+		defer os.RemoveAll(filepath.Dir(filename))
+
+		rp, err := ssmtest.LoadReplayer(filename, nil)
+		if err != nil {
+			t.Fatalf("LoadReplayer() unexpected error: %v", err)
+		}
+		p := &ssmconfig.Provider{SSM: rp}
+
+		var c fixtureConfig
+		if err := p.Process("/service/test", &c); err == nil {
+			t.Errorf("Process() expected error for request with a different path")
+		}
+
+		if err := p.Process("/service/prod", &c); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if err := p.Process("/service/prod", &c); err == nil {
+			t.Errorf("Process() expected error when the recorded interaction was already used")
+		}
+	})
+}
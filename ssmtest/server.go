@@ -0,0 +1,638 @@
+// Package ssmtest provides utilities for testing code that reads from AWS SSM (Parameter
+// Store) without access to an AWS account.
+package ssmtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// The region and account used to build parameter ARNs.
+const (
+	Region  = "us-east-1"
+	Account = "123456789012"
+)
+
+// DefaultKeyID is the KMS key used for SecureString parameters that do not specify one.
+const DefaultKeyID = "alias/aws/ssm"
+
+// Limits enforced by Parameter Store that are mirrored by the Server.
+const (
+	maxGetParametersNames   = 10
+	maxGetByPathResults     = 10
+	maxDescribeResults      = 50
+	defaultDescribeResults  = 10
+	defaultGetByPathResults = 10
+)
+
+// Parameter is a parameter stored in a Server.
+type Parameter struct {
+	Name        string
+	Value       string
+	Type        string // String, StringList, or SecureString. Defaults to String.
+	KeyID       string // Only used by SecureString parameters. Defaults to DefaultKeyID.
+	Description string
+
+	// Version and LastModifiedDate are maintained by the server.
+	Version          int64
+	LastModifiedDate time.Time
+}
+
+// ARN returns the ARN of the parameter.
+func (p Parameter) ARN() string {
+	return "arn:aws:ssm:" + Region + ":" + Account + ":parameter/" + strings.TrimPrefix(p.Name, "/")
+}
+
+// Server is an in-process HTTP server that implements the subset of the AmazonSSM JSON
+// 1.1 protocol used to read and write parameters. It allows a real SSM client to be used
+// in tests by pointing it at a local endpoint.
+//
+// The following actions are supported: GetParameter, GetParameters, GetParametersByPath,
+// PutParameter, and DescribeParameters. Any other action results in an error.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	params map[string]Parameter
+	calls  map[string]int
+	fails  map[string][]*apiError
+	now    func() time.Time
+
+	denyDecrypt bool
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when finished,
+// to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		params: map[string]Parameter{},
+		calls:  map[string]int{},
+		fails:  map[string][]*apiError{},
+		now:    time.Now,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Config returns an aws.Config that directs requests to the server. Static credentials
+// are used and SDK retries are disabled.
+func (s *Server) Config() *aws.Config {
+	return &aws.Config{
+		Endpoint:    aws.String(s.URL),
+		Region:      aws.String(Region),
+		Credentials: credentials.NewStaticCredentials("AKIDSSMTEST", "ssmtest", ""),
+		MaxRetries:  aws.Int(0),
+	}
+}
+
+// Client returns a new SSM client that sends requests to the server.
+func (s *Server) Client() *ssm.SSM {
+	return ssm.New(session.Must(session.NewSession(s.Config())))
+}
+
+// Put stores a String parameter, overwriting any existing parameter with the same name.
+func (s *Server) Put(name, value string) {
+	s.Set(Parameter{Name: name, Value: value})
+}
+
+// PutSecure stores a SecureString parameter encrypted with DefaultKeyID, overwriting any
+// existing parameter with the same name.
+func (s *Server) PutSecure(name, value string) {
+	s.Set(Parameter{Name: name, Value: value, Type: ssm.ParameterTypeSecureString})
+}
+
+// Set stores p, overwriting any existing parameter with the same name. The version of the
+// parameter is incremented and its last modified date is set to the current time.
+func (s *Server) Set(p Parameter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(p)
+}
+
+// Get returns the parameter with the given name.
+func (s *Server) Get(name string) (Parameter, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.params[name]
+	return p, ok
+}
+
+// Delete removes the parameter with the given name.
+func (s *Server) Delete(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.params, name)
+}
+
+// Calls returns the number of requests the server has received for action (e.g.
+// "GetParameters").
+func (s *Server) Calls(action string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[action]
+}
+
+// Fail makes the next n requests for action fail with the given error code, e.g.
+// "ThrottlingException". The codes InternalServerError and ServiceUnavailable are returned
+// with a 5xx status; all others with 400. Failures are queued after any already pending
+// for action.
+func (s *Server) Fail(action, code string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := http.StatusBadRequest
+	switch code {
+	case "InternalServerError":
+		status = http.StatusInternalServerError
+	case "ServiceUnavailable":
+		status = http.StatusServiceUnavailable
+	}
+
+	for i := 0; i < n; i++ {
+		err := errorf(code, "injected failure")
+		err.status = status
+		s.fails[action] = append(s.fails[action], err)
+	}
+}
+
+// DenyDecryption simulates a caller without kms:Decrypt. While deny is true, requests that
+// read a SecureString parameter with decryption fail with AccessDeniedException.
+func (s *Server) DenyDecryption(deny bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.denyDecrypt = deny
+}
+
+// read converts p to its wire representation, failing if p cannot be decrypted.
+func (s *Server) read(p Parameter, decrypt bool) (parameterOutput, *apiError) {
+	if s.denyDecrypt && decrypt && p.Type == ssm.ParameterTypeSecureString {
+		return parameterOutput{}, errorf(
+			"AccessDeniedException",
+			"not authorized to perform kms:Decrypt on key %s for parameter %s", p.KeyID, p.Name,
+		)
+	}
+	return p.output(decrypt), nil
+}
+
+func (s *Server) set(p Parameter) Parameter {
+	if p.Type == "" {
+		p.Type = ssm.ParameterTypeString
+	}
+	if p.Type == ssm.ParameterTypeSecureString && p.KeyID == "" {
+		p.KeyID = DefaultKeyID
+	}
+	if p.Type != ssm.ParameterTypeSecureString {
+		p.KeyID = ""
+	}
+
+	p.Version = s.params[p.Name].Version + 1
+	p.LastModifiedDate = s.now()
+	s.params[p.Name] = p
+	return p
+}
+
+// apiError is an error returned to the client in the format expected by the SDK.
+type apiError struct {
+	Code    string `json:"__type"`
+	Message string `json:"message"`
+
+	status int // HTTP status; 400 if zero
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func errorf(code, format string, args ...interface{}) *apiError {
+	return &apiError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	target := r.Header.Get("X-Amz-Target")
+	if r.Method != http.MethodPost || !strings.HasPrefix(target, "AmazonSSM.") {
+		writeError(w, errorf("UnknownOperationException", "unsupported request %s %s", r.Method, target))
+		return
+	}
+	action := strings.TrimPrefix(target, "AmazonSSM.")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[action]++
+
+	if fails := s.fails[action]; len(fails) > 0 {
+		s.fails[action] = fails[1:]
+		writeError(w, fails[0])
+		return
+	}
+
+	var (
+		output interface{}
+		err    *apiError
+	)
+	switch action {
+	case "GetParameter":
+		var input getParameterInput
+		if err = decode(r, &input); err == nil {
+			output, err = s.getParameter(input)
+		}
+	case "GetParameters":
+		var input getParametersInput
+		if err = decode(r, &input); err == nil {
+			output, err = s.getParameters(input)
+		}
+	case "GetParametersByPath":
+		var input getParametersByPathInput
+		if err = decode(r, &input); err == nil {
+			output, err = s.getParametersByPath(input)
+		}
+	case "PutParameter":
+		var input putParameterInput
+		if err = decode(r, &input); err == nil {
+			output, err = s.putParameter(input)
+		}
+	case "DescribeParameters":
+		var input describeParametersInput
+		if err = decode(r, &input); err == nil {
+			output, err = s.describeParameters(input)
+		}
+	default:
+		err = errorf("UnknownOperationException", "unsupported action %s", action)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	_ = json.NewEncoder(w).Encode(output)
+}
+
+func decode(r *http.Request, v interface{}) *apiError {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorf("SerializationException", "could not decode request: %v", err)
+	}
+	return nil
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	status := err.status
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(err)
+}
+
+type parameterOutput struct {
+	ARN              string
+	LastModifiedDate float64
+	Name             string
+	Type             string
+	Value            string
+	Version          int64
+}
+
+type parameterMetadataOutput struct {
+	Description      string `json:",omitempty"`
+	KeyId            string `json:",omitempty"`
+	LastModifiedDate float64
+	Name             string
+	Tier             string
+	Type             string
+	Version          int64
+}
+
+// output converts p to its wire representation. SecureString values are replaced with an
+// opaque ciphertext when decrypt is false.
+func (p Parameter) output(decrypt bool) parameterOutput {
+	value := p.Value
+	if p.Type == ssm.ParameterTypeSecureString && !decrypt {
+		value = Ciphertext(p)
+	}
+	return parameterOutput{
+		ARN:              p.ARN(),
+		LastModifiedDate: unixTime(p.LastModifiedDate),
+		Name:             p.Name,
+		Type:             p.Type,
+		Value:            value,
+		Version:          p.Version,
+	}
+}
+
+func (p Parameter) metadata() parameterMetadataOutput {
+	return parameterMetadataOutput{
+		Description:      p.Description,
+		KeyId:            p.KeyID,
+		LastModifiedDate: unixTime(p.LastModifiedDate),
+		Name:             p.Name,
+		Tier:             ssm.ParameterTierStandard,
+		Type:             p.Type,
+		Version:          p.Version,
+	}
+}
+
+// Ciphertext returns the value the server reports for a SecureString parameter that is
+// read without decryption.
+func Ciphertext(p Parameter) string {
+	return base64.StdEncoding.EncodeToString([]byte(p.KeyID + ":" + strconv.FormatInt(p.Version, 10) + ":" + p.Value))
+}
+
+func unixTime(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+type getParameterInput struct {
+	Name           string
+	WithDecryption bool
+}
+
+type getParameterOutput struct {
+	Parameter parameterOutput
+}
+
+func (s *Server) getParameter(input getParameterInput) (interface{}, *apiError) {
+	p, ok := s.params[input.Name]
+	if !ok {
+		return nil, errorf(ssm.ErrCodeParameterNotFound, "parameter %s not found", input.Name)
+	}
+	out, err := s.read(p, input.WithDecryption)
+	if err != nil {
+		return nil, err
+	}
+	return getParameterOutput{Parameter: out}, nil
+}
+
+type getParametersInput struct {
+	Names          []string
+	WithDecryption bool
+}
+
+type getParametersOutput struct {
+	InvalidParameters []string
+	Parameters        []parameterOutput
+}
+
+func (s *Server) getParameters(input getParametersInput) (interface{}, *apiError) {
+	if len(input.Names) == 0 || len(input.Names) > maxGetParametersNames {
+		return nil, errorf("ValidationException", "Names must contain between 1 and %d names", maxGetParametersNames)
+	}
+
+	output := getParametersOutput{
+		InvalidParameters: []string{},
+		Parameters:        []parameterOutput{},
+	}
+	for _, name := range input.Names {
+		p, ok := s.params[name]
+		if !ok {
+			output.InvalidParameters = append(output.InvalidParameters, name)
+			continue
+		}
+		out, err := s.read(p, input.WithDecryption)
+		if err != nil {
+			return nil, err
+		}
+		output.Parameters = append(output.Parameters, out)
+	}
+	return output, nil
+}
+
+type getParametersByPathInput struct {
+	Path           string
+	Recursive      bool
+	WithDecryption bool
+	MaxResults     int
+	NextToken      string
+}
+
+type getParametersByPathOutput struct {
+	NextToken  string `json:",omitempty"`
+	Parameters []parameterOutput
+}
+
+func (s *Server) getParametersByPath(input getParametersByPathInput) (interface{}, *apiError) {
+	if !strings.HasPrefix(input.Path, "/") {
+		return nil, errorf("ValidationException", "path %q must begin with /", input.Path)
+	}
+	if input.MaxResults > maxGetByPathResults {
+		return nil, errorf("ValidationException", "MaxResults must be at most %d", maxGetByPathResults)
+	}
+	if input.MaxResults == 0 {
+		input.MaxResults = defaultGetByPathResults
+	}
+
+	var matched []Parameter
+	for _, p := range s.sorted() {
+		if inPath(p.Name, input.Path, input.Recursive) {
+			matched = append(matched, p)
+		}
+	}
+
+	page, next, err := paginate(len(matched), input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	output := getParametersByPathOutput{Parameters: []parameterOutput{}, NextToken: next}
+	for _, p := range matched[page[0]:page[1]] {
+		out, err := s.read(p, input.WithDecryption)
+		if err != nil {
+			return nil, err
+		}
+		output.Parameters = append(output.Parameters, out)
+	}
+	return output, nil
+}
+
+type putParameterInput struct {
+	Name        string
+	Value       string
+	Type        string
+	KeyId       string
+	Description string
+	Overwrite   bool
+}
+
+type putParameterOutput struct {
+	Tier    string
+	Version int64
+}
+
+func (s *Server) putParameter(input putParameterInput) (interface{}, *apiError) {
+	if input.Name == "" || input.Value == "" {
+		return nil, errorf("ValidationException", "Name and Value are required")
+	}
+	switch input.Type {
+	case ssm.ParameterTypeString, ssm.ParameterTypeStringList, ssm.ParameterTypeSecureString:
+	case "":
+		if _, ok := s.params[input.Name]; !ok {
+			return nil, errorf("ValidationException", "Type is required when creating a parameter")
+		}
+		input.Type = s.params[input.Name].Type
+	default:
+		return nil, errorf("ValidationException", "invalid parameter type %q", input.Type)
+	}
+	if _, ok := s.params[input.Name]; ok && !input.Overwrite {
+		return nil, errorf(ssm.ErrCodeParameterAlreadyExists, "parameter %s already exists", input.Name)
+	}
+
+	p := s.set(Parameter{
+		Name:        input.Name,
+		Value:       input.Value,
+		Type:        input.Type,
+		KeyID:       input.KeyId,
+		Description: input.Description,
+	})
+	return putParameterOutput{Tier: ssm.ParameterTierStandard, Version: p.Version}, nil
+}
+
+type parameterStringFilter struct {
+	Key    string
+	Option string
+	Values []string
+}
+
+type describeParametersInput struct {
+	ParameterFilters []parameterStringFilter
+	MaxResults       int
+	NextToken        string
+}
+
+type describeParametersOutput struct {
+	NextToken  string `json:",omitempty"`
+	Parameters []parameterMetadataOutput
+}
+
+func (s *Server) describeParameters(input describeParametersInput) (interface{}, *apiError) {
+	if input.MaxResults > maxDescribeResults {
+		return nil, errorf("ValidationException", "MaxResults must be at most %d", maxDescribeResults)
+	}
+	if input.MaxResults == 0 {
+		input.MaxResults = defaultDescribeResults
+	}
+
+	var matched []Parameter
+	for _, p := range s.sorted() {
+		ok, err := matchFilters(p, input.ParameterFilters)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, p)
+		}
+	}
+
+	page, next, err := paginate(len(matched), input.MaxResults, input.NextToken)
+	if err != nil {
+		return nil, err
+	}
+
+	output := describeParametersOutput{Parameters: []parameterMetadataOutput{}, NextToken: next}
+	for _, p := range matched[page[0]:page[1]] {
+		output.Parameters = append(output.Parameters, p.metadata())
+	}
+	return output, nil
+}
+
+// matchFilters reports whether p matches every filter. Only the Name, Path, Type, and
+// KeyId filter keys are supported.
+func matchFilters(p Parameter, filters []parameterStringFilter) (bool, *apiError) {
+	for _, f := range filters {
+		var field string
+		switch f.Key {
+		case "Name":
+			field = p.Name
+		case "Type":
+			field = p.Type
+		case "KeyId":
+			field = p.KeyID
+		case "Path":
+			recursive := f.Option == "Recursive"
+			if f.Option != "" && f.Option != "Recursive" && f.Option != "OneLevel" {
+				return false, errorf("ValidationException", "invalid Path filter option %q", f.Option)
+			}
+			if !matchAny(f.Values, func(v string) bool { return inPath(p.Name, v, recursive) }) {
+				return false, nil
+			}
+			continue
+		default:
+			return false, errorf("ValidationException", "unsupported filter key %q", f.Key)
+		}
+
+		var match func(string) bool
+		switch f.Option {
+		case "", "Equals":
+			match = func(v string) bool { return field == v }
+		case "BeginsWith":
+			match = func(v string) bool { return strings.HasPrefix(field, v) }
+		default:
+			return false, errorf("ValidationException", "unsupported filter option %q", f.Option)
+		}
+		if !matchAny(f.Values, match) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchAny(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// inPath reports whether name is in the hierarchy rooted at path.
+func inPath(name, path string, recursive bool) bool {
+	path = strings.TrimSuffix(path, "/") + "/"
+	if !strings.HasPrefix(name, path) {
+		return false
+	}
+	return recursive || !strings.Contains(strings.TrimPrefix(name, path), "/")
+}
+
+// paginate returns the bounds of the page described by token and the token for the next
+// page, if any.
+func paginate(n, size int, token string) (page [2]int, next string, err *apiError) {
+	start := 0
+	if token != "" {
+		i, convErr := strconv.Atoi(token)
+		if convErr != nil || i < 0 || i > n {
+			return page, "", errorf("InvalidNextToken", "invalid next token %q", token)
+		}
+		start = i
+	}
+
+	end := start + size
+	if end >= n {
+		end = n
+	} else {
+		next = strconv.Itoa(end)
+	}
+	return [2]int{start, end}, next, nil
+}
+
+func (s *Server) sorted() []Parameter {
+	params := make([]Parameter, 0, len(s.params))
+	for _, p := range s.params {
+		params = append(params, p)
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
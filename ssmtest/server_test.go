@@ -0,0 +1,285 @@
+package ssmtest_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestServer_GetParameter(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/s1", "string1")
+
+	output, err := s.Client().GetParameter(&ssm.GetParameterInput{Name: aws.String("/base/s1")})
+	if err != nil {
+		t.Fatalf("GetParameter() unexpected error: %v", err)
+	}
+
+	p := output.Parameter
+	if *p.Value != "string1" {
+		t.Errorf("GetParameter() unexpected value: want %q, have %q", "string1", *p.Value)
+	}
+	if *p.Type != ssm.ParameterTypeString {
+		t.Errorf("GetParameter() unexpected type: want %q, have %q", ssm.ParameterTypeString, *p.Type)
+	}
+	if *p.Version != 1 {
+		t.Errorf("GetParameter() unexpected version: want %d, have %d", 1, *p.Version)
+	}
+	if want := "arn:aws:ssm:us-east-1:123456789012:parameter/base/s1"; *p.ARN != want {
+		t.Errorf("GetParameter() unexpected ARN: want %q, have %q", want, *p.ARN)
+	}
+	if p.LastModifiedDate == nil || p.LastModifiedDate.IsZero() {
+		t.Errorf("GetParameter() missing LastModifiedDate")
+	}
+
+	_, err = s.Client().GetParameter(&ssm.GetParameterInput{Name: aws.String("/base/missing")})
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != ssm.ErrCodeParameterNotFound {
+		t.Errorf("GetParameter() want %s error, have %v", ssm.ErrCodeParameterNotFound, err)
+	}
+}
+
+func TestServer_GetParameters(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/s1", "string1")
+	s.PutSecure("/base/secret", "hunter2")
+
+	output, err := s.Client().GetParameters(&ssm.GetParametersInput{
+		Names:          aws.StringSlice([]string{"/base/s1", "/base/secret", "/base/missing"}),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("GetParameters() unexpected error: %v", err)
+	}
+
+	values := map[string]string{}
+	for _, p := range output.Parameters {
+		values[*p.Name] = *p.Value
+	}
+	want := map[string]string{"/base/s1": "string1", "/base/secret": "hunter2"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("GetParameters() unexpected values: want %v, have %v", want, values)
+	}
+
+	invalid := aws.StringValueSlice(output.InvalidParameters)
+	if !reflect.DeepEqual(invalid, []string{"/base/missing"}) {
+		t.Errorf("GetParameters() unexpected invalid parameters: %v", invalid)
+	}
+
+	output, err = s.Client().GetParameters(&ssm.GetParametersInput{
+		Names:          aws.StringSlice([]string{"/base/secret"}),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		t.Fatalf("GetParameters() unexpected error: %v", err)
+	}
+	if *output.Parameters[0].Value == "hunter2" {
+		t.Errorf("GetParameters() returned plaintext SecureString without decryption")
+	}
+
+	_, err = s.Client().GetParameters(&ssm.GetParametersInput{
+		Names: aws.StringSlice([]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}),
+	})
+	if err == nil {
+		t.Errorf("GetParameters() expected error for more than 10 names")
+	}
+
+	if have := s.Calls("GetParameters"); have != 3 {
+		t.Errorf("Calls() want %d, have %d", 3, have)
+	}
+}
+
+func TestServer_GetParametersByPath(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	for _, name := range []string{"/a/1", "/a/2", "/a/3", "/a/b/1", "/a/b/2", "/c/1"} {
+		s.Put(name, name)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		recursive bool
+		want      []string
+	}{
+		{name: "one level", recursive: false, want: []string{"/a/1", "/a/2", "/a/3"}},
+		{name: "recursive", recursive: true, want: []string{"/a/1", "/a/2", "/a/3", "/a/b/1", "/a/b/2"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			err := s.Client().GetParametersByPathPages(&ssm.GetParametersByPathInput{
+				Path:       aws.String("/a"),
+				Recursive:  aws.Bool(tt.recursive),
+				MaxResults: aws.Int64(2),
+			}, func(output *ssm.GetParametersByPathOutput, _ bool) bool {
+				for _, p := range output.Parameters {
+					names = append(names, *p.Name)
+				}
+				return true
+			})
+			if err != nil {
+				t.Fatalf("GetParametersByPath() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("GetParametersByPath() want %v, have %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestServer_PutParameter(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	output, err := c.PutParameter(&ssm.PutParameterInput{
+		Name:  aws.String("/base/secret"),
+		Value: aws.String("hunter2"),
+		Type:  aws.String(ssm.ParameterTypeSecureString),
+		KeyId: aws.String("alias/custom"),
+	})
+	if err != nil {
+		t.Fatalf("PutParameter() unexpected error: %v", err)
+	}
+	if *output.Version != 1 {
+		t.Errorf("PutParameter() unexpected version: want %d, have %d", 1, *output.Version)
+	}
+
+	p, _ := s.Get("/base/secret")
+	if p.Value != "hunter2" || p.Type != ssm.ParameterTypeSecureString || p.KeyID != "alias/custom" {
+		t.Errorf("PutParameter() stored unexpected parameter: %+v", p)
+	}
+
+	_, err = c.PutParameter(&ssm.PutParameterInput{
+		Name:  aws.String("/base/secret"),
+		Value: aws.String("hunter3"),
+		Type:  aws.String(ssm.ParameterTypeSecureString),
+	})
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != ssm.ErrCodeParameterAlreadyExists {
+		t.Errorf("PutParameter() want %s error, have %v", ssm.ErrCodeParameterAlreadyExists, err)
+	}
+
+	output, err = c.PutParameter(&ssm.PutParameterInput{
+		Name:      aws.String("/base/secret"),
+		Value:     aws.String("hunter3"),
+		Type:      aws.String(ssm.ParameterTypeSecureString),
+		Overwrite: aws.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("PutParameter() unexpected error: %v", err)
+	}
+	if *output.Version != 2 {
+		t.Errorf("PutParameter() unexpected version: want %d, have %d", 2, *output.Version)
+	}
+}
+
+func TestServer_DescribeParameters(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/s1", "string1")
+	s.Put("/base/s2", "string2")
+	s.PutSecure("/base/secret", "hunter2")
+	s.Put("/other/s1", "string1")
+
+	var metadata []*ssm.ParameterMetadata
+	err := s.Client().DescribeParametersPages(&ssm.DescribeParametersInput{
+		ParameterFilters: []*ssm.ParameterStringFilter{{
+			Key:    aws.String("Name"),
+			Option: aws.String("Equals"),
+			Values: aws.StringSlice([]string{"/base/s1", "/base/secret", "/base/missing"}),
+		}},
+		MaxResults: aws.Int64(1),
+	}, func(output *ssm.DescribeParametersOutput, _ bool) bool {
+		metadata = append(metadata, output.Parameters...)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("DescribeParameters() unexpected error: %v", err)
+	}
+
+	if len(metadata) != 2 {
+		t.Fatalf("DescribeParameters() want 2 parameters, have %d", len(metadata))
+	}
+	if *metadata[0].Name != "/base/s1" || *metadata[1].Name != "/base/secret" {
+		t.Errorf("DescribeParameters() unexpected parameters: %v", metadata)
+	}
+	if *metadata[1].KeyId != ssmtest.DefaultKeyID {
+		t.Errorf("DescribeParameters() unexpected key id: want %q, have %q", ssmtest.DefaultKeyID, *metadata[1].KeyId)
+	}
+}
+
+func TestServer_Fail(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/s1", "string1")
+
+	s.Fail("GetParameters", "ThrottlingException", 1)
+	s.Fail("GetParameters", "InternalServerError", 1)
+
+	input := &ssm.GetParametersInput{Names: aws.StringSlice([]string{"/base/s1"})}
+
+	_, err := s.Client().GetParameters(input)
+	if aerr, ok := err.(awserr.RequestFailure); !ok || aerr.Code() != "ThrottlingException" || aerr.StatusCode() != 400 {
+		t.Errorf("GetParameters() want ThrottlingException with status 400, have %v", err)
+	}
+
+	_, err = s.Client().GetParameters(input)
+	if aerr, ok := err.(awserr.RequestFailure); !ok || aerr.Code() != "InternalServerError" || aerr.StatusCode() != 500 {
+		t.Errorf("GetParameters() want InternalServerError with status 500, have %v", err)
+	}
+
+	if _, err := s.Client().GetParameters(input); err != nil {
+		t.Errorf("GetParameters() unexpected error: %v", err)
+	}
+	if calls := s.Calls("GetParameters"); calls != 3 {
+		t.Errorf("Calls() want 3, have %d", calls)
+	}
+}
+
+func TestServer_DenyDecryption(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/s1", "string1")
+	s.PutSecure("/base/secret", "hunter2")
+	s.DenyDecryption(true)
+
+	_, err := s.Client().GetParameters(&ssm.GetParametersInput{
+		Names:          aws.StringSlice([]string{"/base/s1", "/base/secret"}),
+		WithDecryption: aws.Bool(true),
+	})
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "AccessDeniedException" {
+		t.Errorf("GetParameters() want AccessDeniedException, have %v", err)
+	}
+
+	output, err := s.Client().GetParameters(&ssm.GetParametersInput{
+		Names:          aws.StringSlice([]string{"/base/s1", "/base/secret"}),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		t.Fatalf("GetParameters() unexpected error: %v", err)
+	}
+	if len(output.Parameters) != 2 {
+		t.Errorf("GetParameters() want 2 parameters, have %d", len(output.Parameters))
+	}
+
+	_, err = s.Client().GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String("/base/s1"),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		t.Errorf("GetParameter() unexpected error for String parameter: %v", err)
+	}
+
+	s.DenyDecryption(false)
+	_, err = s.Client().GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String("/base/secret"),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		t.Errorf("GetParameter() unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package ssmconfig
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// ExistingPolicy determines how Store treats parameters that already exist.
+type ExistingPolicy int
+
+const (
+	// SkipExisting leaves existing parameters unchanged.
+	SkipExisting ExistingPolicy = iota
+
+	// OverwriteExisting replaces the values of existing parameters.
+	OverwriteExisting
+
+	// FailExisting stops with an error at the first parameter that already exists.
+	FailExisting
+)
+
+// StoreOptions configures Provider.Store.
+type StoreOptions struct {
+	// Existing determines how parameters that already exist are treated. By default they
+	// are skipped.
+	Existing ExistingPolicy
+
+	// KeyID is the KMS key SecureString parameters are encrypted with. If it is empty the
+	// account's default key, alias/aws/ssm, is used.
+	KeyID string
+}
+
+// StoreAction describes what Store did with the parameter of a field.
+type StoreAction int
+
+const (
+	// StoreEmpty means the field's value is empty, so no parameter was written.
+	StoreEmpty StoreAction = iota
+
+	// StoreCreated means the parameter was created.
+	StoreCreated
+
+	// StoreOverwritten means an existing parameter was overwritten.
+	StoreOverwritten
+
+	// StoreSkipped means the parameter already exists and was left unchanged.
+	StoreSkipped
+)
+
+// String returns the name of a, e.g. "created".
+func (a StoreAction) String() string {
+	switch a {
+	case StoreCreated:
+		return "created"
+	case StoreOverwritten:
+		return "overwritten"
+	case StoreSkipped:
+		return "skipped"
+	default:
+		return "empty"
+	}
+}
+
+// MarshalText encodes a as its name.
+func (a StoreAction) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// StoredField describes the parameter Store wrote for a struct field.
+type StoredField struct {
+	// Field is the name of the struct field.
+	Field string `json:"field"`
+
+	// Parameter is the resolved name of the field's parameter.
+	Parameter string `json:"parameter"`
+
+	// Type is the type the parameter was written as, "String" or "SecureString".
+	Type string `json:"type"`
+
+	Action StoreAction `json:"action"`
+
+	// Version is the version of the parameter that was written. It is only set if the
+	// parameter was created or overwritten.
+	Version int64 `json:"version,omitempty"`
+}
+
+// Store writes the fields of c to Parameter Store with PutParameter. It is the inverse of
+// Process: each field with an `ssm` tag is encoded so that Process would decode it to the
+// same value, and written to its parameter under configPath. c must be a pointer to a
+// struct.
+//
+// Fields tagged `secure:"true"` or `sensitive:"true"`, and Secret fields, are written as
+// SecureString parameters encrypted with opts.KeyID. Other fields are written as String
+// parameters, except that an existing SecureString parameter that is overwritten stays a
+// SecureString; this requires ssm:DescribeParameters. Fields with an empty value are not
+// written, as Parameter Store does not allow empty values.
+//
+// Fields are written in order. If a write fails, the fields handled so far are returned
+// along with the error. Written parameters are removed from p.Cache. p.Retry only applies
+// when opts.Existing is OverwriteExisting, as otherwise a retry cannot tell whether the
+// parameter was created by an earlier attempt.
+func (p *Provider) Store(ctx context.Context, configPath string, c interface{}, opts StoreOptions) ([]StoredField, error) {
+	v, err := structValue(c)
+	if err != nil {
+		return nil, err
+	}
+	spec := buildStructSpec(configPath, v.Type())
+
+	// The parameters of fields that would be written as Strings are described first, so
+	// that overwriting never downgrades a SecureString.
+	var existing map[string]*ssm.ParameterMetadata
+	if opts.Existing == OverwriteExisting {
+		var names []string
+		for _, field := range spec {
+			if !field.secure && !field.sensitive {
+				names = append(names, field.name)
+			}
+		}
+		if len(names) > 0 {
+			if existing, err = p.describeParameters(ctx, names); err != nil {
+				return nil, errors.Wrap(err, "ssmconfig: could not describe parameters")
+			}
+		}
+	}
+
+	stored := make([]StoredField, 0, len(spec))
+	for _, field := range spec {
+		value, err := encodeValue(v.Field(field.index))
+		if err != nil {
+			return stored, errors.Wrapf(err, "ssmconfig: error encoding field %s", field.field)
+		}
+
+		sf := StoredField{Field: field.field, Parameter: field.name, Type: ssm.ParameterTypeString}
+		if m, ok := existing[field.name]; field.secure || field.sensitive ||
+			(ok && aws.StringValue(m.Type) == ssm.ParameterTypeSecureString) {
+			sf.Type = ssm.ParameterTypeSecureString
+		}
+		if value != "" {
+			if err := p.putParameter(ctx, &sf, value, opts); err != nil {
+				return stored, err
+			}
+		}
+		stored = append(stored, sf)
+
+		if p.Logger != nil {
+			p.Logger.LogAttrs(ctx, slog.LevelDebug, "ssmconfig: stored field",
+				slog.String("field", sf.Field),
+				slog.String("parameter", sf.Parameter),
+				slog.String("type", sf.Type),
+				slog.String("action", sf.Action.String()),
+			)
+		}
+	}
+	return stored, nil
+}
+
+// putParameter writes value to the parameter described by sf, and records the result in
+// sf.
+func (p *Provider) putParameter(ctx context.Context, sf *StoredField, value string, opts StoreOptions) error {
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(sf.Parameter),
+		Value:     aws.String(value),
+		Type:      aws.String(sf.Type),
+		Overwrite: aws.Bool(opts.Existing == OverwriteExisting),
+	}
+	if sf.Type == ssm.ParameterTypeSecureString && opts.KeyID != "" {
+		input.KeyId = aws.String(opts.KeyID)
+	}
+
+	// A put that does not overwrite is not retried: if an attempt that failed with a
+	// transient error created the parameter, the retry would fail with
+	// ParameterAlreadyExists and the field would be reported as skipped.
+	retry := p.Retry
+	if !aws.BoolValue(input.Overwrite) {
+		retry = nil
+	}
+
+	var output *ssm.PutParameterOutput
+	_, err := retry.do(ctx, func() error {
+		if err := p.wait(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		output, err = p.SSM.PutParameterWithContext(ctx, input)
+		return err
+	})
+	if aerr, ok := errors.Cause(err).(awserr.Error); ok && aerr.Code() == ssm.ErrCodeParameterAlreadyExists {
+		if opts.Existing == SkipExisting {
+			sf.Action = StoreSkipped
+			return nil
+		}
+		return errors.Errorf("ssmconfig: parameter %s for field %s already exists", sf.Parameter, sf.Field)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "ssmconfig: could not put parameter %s for field %s", sf.Parameter, sf.Field)
+	}
+
+	if p.Cache != nil {
+		p.Cache.Invalidate(sf.Parameter)
+	}
+
+	sf.Version = aws.Int64Value(output.Version)
+	sf.Action = StoreCreated
+	if sf.Version > 1 {
+		sf.Action = StoreOverwritten
+	}
+	return nil
+}
+
+// encodeValue returns the string that setValue decodes into the value of v.
+func encodeValue(v reflect.Value) (string, error) {
+	return encoderFor(v.Type())(v)
+}
+
+// encodeFunc encodes v as a string. It is the inverse of a decodeFunc.
+type encodeFunc func(v reflect.Value) (string, error)
+
+// encoderFor returns the encodeFunc for values of type t.
+func encoderFor(t reflect.Type) encodeFunc {
+	if t.Implements(secretEncoderType) {
+		return encodeSecret
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) (string, error) { return v.String(), nil }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) (string, error) { return strconv.FormatInt(v.Int(), 10), nil }
+	case reflect.Float32:
+		return encodeFloat(32)
+	case reflect.Float64:
+		return encodeFloat(64)
+	case reflect.Bool:
+		return func(v reflect.Value) (string, error) { return strconv.FormatBool(v.Bool()), nil }
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return func(v reflect.Value) (string, error) { return string(v.Bytes()), nil }
+		}
+		return encodeUnsupported
+	default:
+		return encodeUnsupported
+	}
+}
+
+func encodeFloat(bitSize int) encodeFunc {
+	return func(v reflect.Value) (string, error) {
+		return strconv.FormatFloat(v.Float(), 'g', -1, bitSize), nil
+	}
+}
+
+func encodeUnsupported(v reflect.Value) (string, error) {
+	return "", errors.Errorf("could not encode type %v", v.Type())
+}
@@ -0,0 +1,252 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ssm"
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+type storeConfig struct {
+	String   string                   `ssm:"/strings/s1"`
+	Empty    string                   `ssm:"/strings/empty"`
+	Int      int                      `ssm:"/int/i1"`
+	Int8     int8                     `ssm:"/int/i8"`
+	Duration time.Duration            `ssm:"/duration/d1"`
+	Float32  float32                  `ssm:"/float/f32"`
+	Float64  float64                  `ssm:"/float/f64"`
+	Bool     bool                     `ssm:"/bool/b1"`
+	Bytes    []byte                   `ssm:"/bytes/b1"`
+	Token    string                   `ssm:"/secure/token" sensitive:"true"`
+	Password ssmconfig.Secret[string] `ssm:"/secure/password"`
+	Ignored  string
+}
+
+func TestProvider_Store(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+
+	want := storeConfig{
+		String:   "string",
+		Int:      -42,
+		Int8:     8,
+		Duration: 3 * time.Second,
+		Float32:  1.1,
+		Float64:  math.Pi,
+		Bool:     true,
+		Bytes:    []byte("bytes"),
+		Token:    "token",
+		Password: ssmconfig.NewSecret("hunter2"),
+		Ignored:  "ignored",
+	}
+
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	stored, err := p.Store(context.Background(), "/base", &want, ssmconfig.StoreOptions{KeyID: "alias/config"})
+	if err != nil {
+		t.Fatalf("Store() unexpected error: %v", err)
+	}
+	if len(stored) != 11 {
+		t.Fatalf("Store() want 11 fields, have %d", len(stored))
+	}
+	for _, sf := range stored {
+		wantAction := ssmconfig.StoreCreated
+		if sf.Field == "Empty" {
+			wantAction = ssmconfig.StoreEmpty
+		}
+		if sf.Action != wantAction {
+			t.Errorf("Store() field %s: want action %v, have %v", sf.Field, wantAction, sf.Action)
+		}
+	}
+
+	for name, wantType := range map[string]string{
+		"/base/strings/s1":      ssm.ParameterTypeString,
+		"/base/secure/token":    ssm.ParameterTypeSecureString,
+		"/base/secure/password": ssm.ParameterTypeSecureString,
+		"/base/duration/d1":     ssm.ParameterTypeString,
+		"/base/float/f32":       ssm.ParameterTypeString,
+	} {
+		param, ok := s.Get(name)
+		if !ok {
+			t.Errorf("Store() did not write %s", name)
+			continue
+		}
+		if param.Type != wantType {
+			t.Errorf("Store() %s: want type %s, have %s", name, wantType, param.Type)
+		}
+		if wantType == ssm.ParameterTypeSecureString && param.KeyID != "alias/config" {
+			t.Errorf("Store() %s: want key alias/config, have %s", name, param.KeyID)
+		}
+	}
+	if _, ok := s.Get("/base/strings/empty"); ok {
+		t.Errorf("Store() wrote an empty value")
+	}
+
+	var have storeConfig
+	if err := p.Process("/base", &have); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+	have.Ignored = want.Ignored
+	if have.Password.Reveal() != want.Password.Reveal() {
+		t.Errorf("Process() unexpected password after Store()")
+	}
+	have.Password, want.Password = ssmconfig.Secret[string]{}, ssmconfig.Secret[string]{}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("Process() after Store() unexpected config:\nwant %+v\nhave %+v", want, have)
+	}
+}
+
+func TestProvider_Store_existing(t *testing.T) {
+	type config struct {
+		A string `ssm:"/a"`
+		B string `ssm:"/b"`
+	}
+
+	s := ssmtest.NewServer()
+	defer s.Close()
+	p := &ssmconfig.Provider{SSM: s.Client(), Cache: ssmconfig.NewCache(time.Minute)}
+	ctx := context.Background()
+
+	s.Put("/base/a", "old")
+	var c config
+	if err := p.Process("/base", &c); err != nil {
+		t.Fatalf("Process() unexpected error: %v", err)
+	}
+
+	c = config{A: "new", B: "new"}
+
+	t.Run("skip", func(t *testing.T) {
+		stored, err := p.Store(ctx, "/base", &c, ssmconfig.StoreOptions{})
+		if err != nil {
+			t.Fatalf("Store() unexpected error: %v", err)
+		}
+		if stored[0].Action != ssmconfig.StoreSkipped || stored[1].Action != ssmconfig.StoreCreated {
+			t.Errorf("Store() unexpected actions: %+v", stored)
+		}
+		if param, _ := s.Get("/base/a"); param.Value != "old" {
+			t.Errorf("Store() overwrote existing parameter")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		stored, err := p.Store(ctx, "/base", &c, ssmconfig.StoreOptions{Existing: ssmconfig.FailExisting})
+		if err == nil || !strings.Contains(err.Error(), "/base/a for field A already exists") {
+			t.Errorf("Store() want already exists error, have %v", err)
+		}
+		if len(stored) != 0 {
+			t.Errorf("Store() want no stored fields, have %+v", stored)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		stored, err := p.Store(ctx, "/base", &c, ssmconfig.StoreOptions{Existing: ssmconfig.OverwriteExisting})
+		if err != nil {
+			t.Fatalf("Store() unexpected error: %v", err)
+		}
+		if stored[0].Action != ssmconfig.StoreOverwritten || stored[0].Version != 2 {
+			t.Errorf("Store() unexpected result: %+v", stored[0])
+		}
+
+		// The cached value of /base/a was invalidated.
+		var have config
+		if err := p.Process("/base", &have); err != nil {
+			t.Fatalf("Process() unexpected error: %v", err)
+		}
+		if have != c {
+			t.Errorf("Process() want %+v, have %+v", c, have)
+		}
+	})
+}
+
+func TestProvider_Store_existingSecureString(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.PutSecure("/base/db/password", "old-secret")
+	p := &ssmconfig.Provider{SSM: s.Client()}
+
+	c := struct {
+		Password string `ssm:"/db/password"`
+	}{Password: "new-secret"}
+	stored, err := p.Store(context.Background(), "/base", &c, ssmconfig.StoreOptions{Existing: ssmconfig.OverwriteExisting})
+	if err != nil {
+		t.Fatalf("Store() unexpected error: %v", err)
+	}
+	if stored[0].Action != ssmconfig.StoreOverwritten || stored[0].Type != ssm.ParameterTypeSecureString {
+		t.Errorf("Store() unexpected result: %+v", stored[0])
+	}
+
+	param, _ := s.Get("/base/db/password")
+	if param.Type != ssm.ParameterTypeSecureString || param.Value != "new-secret" {
+		t.Errorf("Store() want SecureString %q, have %s %q", "new-secret", param.Type, param.Value)
+	}
+}
+
+func TestProvider_Store_retry(t *testing.T) {
+	type config struct {
+		A string `ssm:"/a"`
+	}
+	c := config{A: "new"}
+
+	newProvider := func(s *ssmtest.Server) *ssmconfig.Provider {
+		retry := ssmconfig.NewRetryPolicy(3)
+		retry.Sleep = func(context.Context, time.Duration) error { return nil }
+		return &ssmconfig.Provider{SSM: s.Client(), Retry: retry}
+	}
+
+	t.Run("create is not retried", func(t *testing.T) {
+		s := ssmtest.NewServer()
+		defer s.Close()
+		s.Fail("PutParameter", "InternalServerError", 1)
+
+		_, err := newProvider(s).Store(context.Background(), "/base", &c, ssmconfig.StoreOptions{})
+		if err == nil || !strings.Contains(err.Error(), "InternalServerError") {
+			t.Errorf("Store() want InternalServerError, have %v", err)
+		}
+		if n := s.Calls("PutParameter"); n != 1 {
+			t.Errorf("Store() want 1 PutParameter call, have %d", n)
+		}
+	})
+
+	t.Run("overwrite is retried", func(t *testing.T) {
+		s := ssmtest.NewServer()
+		defer s.Close()
+		s.Put("/base/a", "old")
+		s.Fail("PutParameter", "InternalServerError", 1)
+
+		stored, err := newProvider(s).Store(context.Background(), "/base", &c, ssmconfig.StoreOptions{Existing: ssmconfig.OverwriteExisting})
+		if err != nil {
+			t.Fatalf("Store() unexpected error: %v", err)
+		}
+		if stored[0].Action != ssmconfig.StoreOverwritten {
+			t.Errorf("Store() unexpected result: %+v", stored[0])
+		}
+		if n := s.Calls("PutParameter"); n != 2 {
+			t.Errorf("Store() want 2 PutParameter calls, have %d", n)
+		}
+	})
+}
+
+func TestProvider_Store_errors(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	ctx := context.Background()
+
+	if _, err := p.Store(ctx, "/base", storeConfig{}, ssmconfig.StoreOptions{}); err == nil {
+		t.Errorf("Store() expected error for non-pointer")
+	}
+
+	type unsupported struct {
+		Secret ssmconfig.Secret[[]string] `ssm:"/secret"`
+	}
+	c := unsupported{Secret: ssmconfig.NewSecret([]string{"hunter2"})}
+	_, err := p.Store(ctx, "/base", &c, ssmconfig.StoreOptions{})
+	if err == nil || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("Store() want error without the secret value, have %v", err)
+	}
+}
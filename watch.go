@@ -0,0 +1,259 @@
+package ssmconfig
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/pkg/errors"
+)
+
+// Update is a new version of a config delivered by a Watcher.
+type Update struct {
+	// Config is a pointer to a new, fully populated copy of the config struct.
+	Config interface{}
+
+	// Changed is the names of the struct fields whose values changed.
+	Changed []string
+}
+
+// Watcher periodically re-processes a config struct and reports changes to its values.
+//
+// Each change is delivered as a new copy of the config struct. A struct that has been
+// delivered is never modified, so it can be read without synchronization.
+//
+// Changes are detected by comparing parameter versions reported by DescribeParameters, so
+// the ssm:DescribeParameters permission is required. Only the parameters whose versions
+// have moved are fetched and decrypted, and only their fields are decoded again.
+type Watcher struct {
+	// OnChange is called with each new version of the config. It is called from the
+	// goroutine that called Check or Run.
+	OnChange func(Update)
+
+	// OnError is called by Run when a check fails. The current config is left unchanged.
+	OnError func(error)
+
+	provider *Provider
+	spec     structSpec
+	typ      reflect.Type
+
+	// checkMu serializes checks and the delivery of their updates. current and versions
+	// are only replaced by a check, so they can be read while it is held.
+	checkMu sync.Mutex
+
+	// mu guards current and versions against Current, which does not hold checkMu. It is
+	// only held while they are read or replaced, never while parameters are fetched.
+	mu       sync.Mutex
+	current  reflect.Value
+	versions map[string]paramVersion
+}
+
+// paramVersion identifies the version of a parameter that a field was decoded from.
+type paramVersion struct {
+	valid   bool
+	version int64
+}
+
+func newParamVersion(param *ssm.Parameter) paramVersion {
+	if param == nil {
+		return paramVersion{}
+	}
+	return paramVersion{valid: true, version: aws.Int64Value(param.Version)}
+}
+
+// unchanged reports whether a field decoded from version v can be kept, rather than
+// decoded again, when the parameter is at version next.
+func (v paramVersion) unchanged(next paramVersion) bool {
+	// A version of 0 is unknown, so the parameter must always be decoded.
+	return v == next && (!v.valid || v.version != 0)
+}
+
+// NewWatcher processes c using p and returns a Watcher that checks the same parameters
+// for changes. c must be a pointer to a struct. ctx is only used for the initial load.
+//
+// c is not modified after NewWatcher returns. If a *StaleError is returned c is populated
+// and the Watcher can be used.
+func NewWatcher(ctx context.Context, p *Provider, configPath string, c interface{}) (*Watcher, error) {
+	v, err := structValue(c)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		provider: p,
+		spec:     buildStructSpec(configPath, v.Type()),
+		typ:      v.Type(),
+		versions: map[string]paramVersion{},
+	}
+
+	decrypted, plain := p.requestNames(w.spec, nil)
+	res, err := p.getParameters(ctx, decrypted, plain)
+	stale, _ := err.(*StaleError)
+	if err != nil && stale == nil {
+		return nil, errors.Wrap(err, "ssmconfig: could not get parameters")
+	}
+
+	if stale == nil {
+		if err := p.checkKeyIDs(ctx, w.spec, res.params, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, field := range w.spec {
+		if _, err := w.spec.setField(ctx, p, structTarget{v}, i, res.params, res.invalid); err != nil {
+			return nil, err
+		}
+		w.versions[field.name] = newParamVersion(res.params[field.name])
+	}
+
+	w.current = reflect.New(w.typ)
+	w.current.Elem().Set(v)
+
+	if stale != nil {
+		return w, stale
+	}
+	return w, nil
+}
+
+// Current returns a pointer to the current version of the config.
+func (w *Watcher) Current() interface{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current.Interface()
+}
+
+// Run checks for changes every interval until ctx is done. Errors are reported to
+// OnError.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if err := w.Check(ctx); err != nil && w.OnError != nil {
+				w.OnError(err)
+			}
+		}
+	}
+}
+
+// Check fetches the parameters once and calls OnChange if any values have changed. If
+// an error is returned the current config is left unchanged.
+func (w *Watcher) Check(ctx context.Context) error {
+	w.checkMu.Lock()
+	defer w.checkMu.Unlock()
+
+	update, err := w.check(ctx)
+	if err != nil || update == nil {
+		return err
+	}
+
+	if w.OnChange != nil {
+		w.OnChange(*update)
+	}
+	return nil
+}
+
+// check must be called with w.checkMu held.
+func (w *Watcher) check(ctx context.Context) (*Update, error) {
+	current, prev := w.current, w.versions
+
+	// Parameter metadata is checked first so that only the parameters whose versions have
+	// moved are fetched, and SecureString parameters are only decrypted when they change.
+	metadata, err := w.provider.describeParameters(ctx, w.spec.names())
+	if err != nil {
+		return nil, errors.Wrap(err, "ssmconfig: could not describe parameters")
+	}
+
+	versions := make(map[string]paramVersion, len(prev))
+	var moved []string
+	for _, name := range w.spec.names() {
+		if _, ok := versions[name]; ok {
+			continue
+		}
+
+		next := paramVersion{}
+		if m, ok := metadata[name]; ok {
+			next = paramVersion{valid: true, version: aws.Int64Value(m.Version)}
+		}
+		versions[name] = next
+
+		if next.valid && !prev[name].unchanged(next) {
+			moved = append(moved, name)
+		}
+	}
+
+	params := map[string]*ssm.Parameter{}
+	invalidParams := map[string]struct{}{}
+	if len(moved) > 0 {
+		if w.provider.Cache != nil {
+			w.provider.Cache.Invalidate(moved...)
+		}
+		only := make(map[string]struct{}, len(moved))
+		for _, name := range moved {
+			only[name] = struct{}{}
+		}
+		decrypted, plain := w.provider.requestNames(w.spec, only)
+		res, err := w.provider.getParameters(ctx, decrypted, plain)
+		if err != nil {
+			return nil, errors.Wrap(err, "ssmconfig: could not get parameters")
+		}
+		params, invalidParams = res.params, res.invalid
+
+		if err := w.provider.checkKeyIDs(ctx, w.spec, params, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	next := reflect.New(w.typ)
+	next.Elem().Set(current.Elem())
+
+	var changed []string
+	for i, field := range w.spec {
+		version := versions[field.name]
+		if param, ok := params[field.name]; ok {
+			version = newParamVersion(param)
+		} else if version.valid {
+			// The parameter was not fetched because it is unchanged, or it was deleted
+			// after it was described.
+			if _, ok := invalidParams[field.name]; !ok {
+				continue
+			}
+			version = paramVersion{}
+		} else {
+			invalidParams[field.name] = struct{}{}
+		}
+		versions[field.name] = version
+
+		if prev[field.name].unchanged(version) {
+			continue
+		}
+
+		f := next.Elem().Field(field.index)
+		f.Set(reflect.Zero(f.Type()))
+		if _, err := w.spec.setField(ctx, w.provider, structTarget{next.Elem()}, i, params, invalidParams); err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(f.Interface(), current.Elem().Field(field.index).Interface()) {
+			changed = append(changed, field.field)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.versions = versions
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	w.current = next
+	return &Update{Config: next.Interface(), Changed: changed}, nil
+}
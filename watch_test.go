@@ -0,0 +1,239 @@
+package ssmconfig_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	ssmconfig "github.com/ianlopshire/go-ssm-config"
+	"github.com/ianlopshire/go-ssm-config/ssmtest"
+)
+
+func TestWatcher(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.Put("/base/int/i1", "42")
+	s.Put("/base/strings/required", "required")
+
+	type config struct {
+		S1       string `ssm:"/strings/s1" default:"default"`
+		I1       int    `ssm:"/int/i1"`
+		Required string `ssm:"/strings/required" required:"true"`
+	}
+
+	var initial config
+	w, err := ssmconfig.NewWatcher(context.Background(), &ssmconfig.Provider{SSM: s.Client()}, "/base", &initial)
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+	want := config{S1: "string1", I1: 42, Required: "required"}
+	if initial != want {
+		t.Fatalf("NewWatcher() want %+v, have %+v", want, initial)
+	}
+
+	var updates []ssmconfig.Update
+	w.OnChange = func(u ssmconfig.Update) { updates = append(updates, u) }
+
+	check := func(wantChanged []string, want config) {
+		t.Helper()
+		updates = nil
+		if err := w.Check(context.Background()); err != nil {
+			t.Fatalf("Check() unexpected error: %v", err)
+		}
+
+		if wantChanged == nil {
+			if len(updates) != 0 {
+				t.Errorf("Check() unexpected update: %+v", updates[0])
+			}
+			return
+		}
+
+		if len(updates) != 1 {
+			t.Fatalf("Check() want 1 update, have %d", len(updates))
+		}
+		if !reflect.DeepEqual(updates[0].Changed, wantChanged) {
+			t.Errorf("Check() want changed %v, have %v", wantChanged, updates[0].Changed)
+		}
+		if have := *updates[0].Config.(*config); have != want {
+			t.Errorf("Check() want %+v, have %+v", want, have)
+		}
+		if w.Current() != updates[0].Config {
+			t.Errorf("Current() does not return the latest update")
+		}
+	}
+
+	check(nil, config{})
+	if have := s.Calls("GetParameters"); have != 1 {
+		t.Errorf("Check() fetched unchanged parameters: want %d GetParameters calls, have %d", 1, have)
+	}
+
+	s.Put("/base/strings/s1", "changed")
+	check([]string{"S1"}, config{S1: "changed", I1: 42, Required: "required"})
+	if initial != want {
+		t.Errorf("Check() modified the initial config: %+v", initial)
+	}
+
+	// A new version with the same value is not a change.
+	s.Put("/base/int/i1", "42")
+	check(nil, config{})
+
+	s.Delete("/base/strings/s1")
+	s.Put("/base/int/i1", "43")
+	check([]string{"S1", "I1"}, config{S1: "default", I1: 43, Required: "required"})
+
+	s.Delete("/base/strings/required")
+	updates = nil
+	if err := w.Check(context.Background()); err == nil {
+		t.Errorf("Check() expected error for missing required parameter")
+	}
+	if len(updates) != 0 {
+		t.Errorf("Check() unexpected update after error")
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+
+	var c struct {
+		S1 string `ssm:"/strings/s1"`
+	}
+	w, err := ssmconfig.NewWatcher(context.Background(), &ssmconfig.Provider{SSM: s.Client()}, "/base", &c)
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan ssmconfig.Update, 1)
+	w.OnChange = func(u ssmconfig.Update) {
+		changes <- u
+		cancel()
+	}
+	s.Put("/base/strings/s1", "changed")
+
+	if err := w.Run(ctx, time.Millisecond); err != context.Canceled {
+		t.Errorf("Run() want %v, have %v", context.Canceled, err)
+	}
+
+	select {
+	case u := <-changes:
+		if !reflect.DeepEqual(u.Changed, []string{"S1"}) {
+			t.Errorf("Run() unexpected changed fields: %v", u.Changed)
+		}
+	default:
+		t.Errorf("Run() did not deliver an update")
+	}
+}
+
+func TestWatcher_Current_duringCheck(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+
+	type config struct {
+		S1 string `ssm:"/strings/s1"`
+	}
+	p := &ssmconfig.Provider{SSM: s.Client()}
+	var c config
+	w, err := ssmconfig.NewWatcher(context.Background(), p, "/base", &c)
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	client := &blockingSSMClient{
+		SSMAPI:  s.Client(),
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	p.SSM = client
+	s.Put("/base/strings/s1", "changed")
+
+	errs := make(chan error, 1)
+	go func() { errs <- w.Check(context.Background()) }()
+	<-client.started
+
+	current := make(chan *config, 1)
+	go func() { current <- w.Current().(*config) }()
+	select {
+	case c := <-current:
+		if c.S1 != "string1" {
+			t.Errorf("Current() unexpected value during check: %q", c.S1)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Current() blocked while a check was fetching parameters")
+	}
+
+	close(client.release)
+	if err := <-errs; err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+	if have := w.Current().(*config).S1; have != "changed" {
+		t.Errorf("Current() want %q, have %q", "changed", have)
+	}
+}
+
+type recordingSSMClient struct {
+	ssmiface.SSMAPI
+	names [][]string
+}
+
+func (c *recordingSSMClient) GetParameters(input *ssm.GetParametersInput) (*ssm.GetParametersOutput, error) {
+	c.names = append(c.names, aws.StringValueSlice(input.Names))
+	return c.SSMAPI.GetParameters(input)
+}
+
+func (c *recordingSSMClient) GetParametersWithContext(ctx aws.Context, input *ssm.GetParametersInput, opts ...request.Option) (*ssm.GetParametersOutput, error) {
+	c.names = append(c.names, aws.StringValueSlice(input.Names))
+	return c.SSMAPI.GetParametersWithContext(ctx, input, opts...)
+}
+
+func TestWatcher_fetchesOnlyMovedParameters(t *testing.T) {
+	s := ssmtest.NewServer()
+	defer s.Close()
+	s.Put("/base/strings/s1", "string1")
+	s.PutSecure("/base/strings/secret", "hunter2")
+
+	type config struct {
+		S1     string `ssm:"/strings/s1"`
+		S2     string `ssm:"/strings/s2" default:"default"`
+		Secret string `ssm:"/strings/secret"`
+	}
+
+	client := &recordingSSMClient{SSMAPI: s.Client()}
+	var c config
+	w, err := ssmconfig.NewWatcher(context.Background(), &ssmconfig.Provider{SSM: client}, "/base", &c)
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	s.Put("/base/strings/s1", "changed")
+	s.Put("/base/strings/s2", "created")
+	if err := w.Check(context.Background()); err != nil {
+		t.Fatalf("Check() unexpected error: %v", err)
+	}
+
+	want := [][]string{
+		{"/base/strings/s1", "/base/strings/s2", "/base/strings/secret"},
+		{"/base/strings/s1", "/base/strings/s2"},
+	}
+	if !reflect.DeepEqual(client.names, want) {
+		t.Errorf("Check() want GetParameters names %v, have %v", want, client.names)
+	}
+
+	have := *w.Current().(*config)
+	if wantConfig := (config{S1: "changed", S2: "created", Secret: "hunter2"}); have != wantConfig {
+		t.Errorf("Current() want %+v, have %+v", wantConfig, have)
+	}
+	if have := s.Calls("DescribeParameters"); have != 1 {
+		t.Errorf("Check() want %d DescribeParameters calls, have %d", 1, have)
+	}
+}